@@ -0,0 +1,138 @@
+package fungen
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"text/template"
+)
+
+// Options - the options of Generate. Types, Pairs and Methods are given as the -types, -pairs and -methods flags and
+// Flags sets the other flags by name, eg. {"funcs": "true", "filename": "models/fungen_auto.go"}
+type Options struct {
+	Package string
+	Types   string
+	Pairs   string
+	Methods string
+	Flags   map[string]string
+}
+
+// unsupportedFlags - the flags which Generate does not support, since they do not generate a single file in memory
+var unsupportedFlags = map[string]bool{
+	"check":        true,
+	"diff":         true,
+	"force":        true,
+	"force-write":  true,
+	"subpackage":   true,
+	"watch":        true,
+	"test":         true,
+	"version":      true,
+	"split":        true,
+	"config":       true,
+	"vet":          true,
+	"gen-tests":    true,
+	"gen-examples": true,
+	"gen-fuzz":     true,
+	"gen-props":    true,
+	"gen-doc":      true,
+	"docs":         true,
+	"plan":         true,
+	"cpuprofile":   true,
+	"memprofile":   true,
+}
+
+// Generate - generate the source of the file for the options opts, as fungen would write it to -filename, without
+// writing it. Without types and pairs, the types are read from the directive comments of the package in the directory
+// of -filename. The configuration file is not read. The flags of fungen are restored once it returns, so that it does
+// not change the command line. Generate must not be called concurrently
+func Generate(opts Options) ([]byte, error) {
+	defer restoreFlags(flagSet, getChangedFlags())
+	resetState()
+
+	values := map[string]string{"package": opts.Package, "types": opts.Types, "pairs": opts.Pairs, "methods": opts.Methods}
+	for name, value := range opts.Flags {
+		if unsupportedFlags[name] {
+			return nil, fmt.Errorf("fungen: flag -%s is not supported by Generate", name)
+		}
+		values[name] = value
+	}
+	for name, value := range values {
+		if value == "" {
+			continue
+		}
+		if err := flagSet.Set(name, value); err != nil {
+			return nil, fmt.Errorf("fungen: %s", err)
+		}
+	}
+	commandLine = getCommandLine(flagSet)
+
+	files, err := generateFiles()
+	if err != nil {
+		return nil, fmt.Errorf("fungen: %s", err)
+	}
+	return []byte(files[*outputName]), nil
+}
+
+// resetState - reset the flags to their default values, as not set, and the state derived from them, so that the
+// generation does not depend on the previous ones
+func resetState() {
+	set := flag.NewFlagSet(flagSet.Name(), flagSet.ErrorHandling())
+	flagSet.VisitAll(func(f *flag.Flag) {
+		f.Value.Set(f.DefValue)
+		set.Var(f.Value, f.Name, f.Usage)
+	})
+	flagSet = set
+
+	commandLine = ""
+	listNames = map[string]string{}
+	pairNames = map[[2]string]string{}
+	typeMethods = map[string]string{}
+	existLists = map[string]bool{}
+	typeTraits = map[string]map[string]bool{}
+	detectedTraits = map[string]map[string]bool{}
+	packageFiles, packageFset = nil, nil
+	fileImportPaths, dependencyImportPaths, standardImportPaths, moduleImportPaths = nil, nil, nil, nil
+	methodTemplates = map[string]*template.Template{}
+	skippedProblems = []string{}
+}
+
+// getChangedFlags - get the values of the flags which are not set to their default value, by name
+func getChangedFlags() map[string]string {
+	values := map[string]string{}
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if value := f.Value.String(); value != f.DefValue {
+			values[f.Name] = value
+		}
+	})
+	return values
+}
+
+// restoreFlags - make set the flag set of fungen again, with the flags given their values and the other flags their
+// default value, so that the flags are as they were before the state was reset
+func restoreFlags(set *flag.FlagSet, values map[string]string) {
+	flagSet = set
+	flagSet.VisitAll(func(f *flag.Flag) {
+		f.Value.Set(f.DefValue)
+		if value, ok := values[f.Name]; ok {
+			f.Value.Set(value)
+		}
+	})
+}
+
+// RegisterGenerator - add the generator g of the method name to the generated methods, after the built-in ones, so that
+// it can be selected with -methods. The name must be an exported identifier which is not already used
+func RegisterGenerator(name string, g Generator) error {
+	if !token.IsIdentifier(name) || !ast.IsExported(name) {
+		return fmt.Errorf("generator name '%s' is not an exported identifier", name)
+	}
+	if len(generators.Filter(func(gen Generator) bool { return gen.Name == name })) > 0 {
+		return fmt.Errorf("generator '%s' is already defined", name)
+	}
+	if g.Method == nil {
+		return fmt.Errorf("generator '%s' has no Method", name)
+	}
+	g.Name = name
+	generators = append(generators, g)
+	return nil
+}
@@ -0,0 +1,132 @@
+package fungen
+
+import (
+	"go/ast"
+	"go/token"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// sectionBegin - the prefix of the line starting a section of a file generated in -append mode, followed by the
+	// command line which generated the section
+	sectionBegin = "// fungen:begin "
+	// sectionEnd - the line ending a section of a file generated in -append mode
+	sectionEnd = "// fungen:end"
+)
+
+// getAppendedSource - get the generated source src as a section of the file path, keeping the other sections if the file
+// was generated in -append mode and replacing the section of the current command line or, if its command line was
+// edited, eg. to change the methods, the section which generates the same types. The imports of the sections are merged
+func getAppendedSource(path, src string) string {
+	head, body := splitSourceBody(src)
+	section := sectionBegin + commandLine + "\n" + body + "\n" + sectionEnd + "\n"
+
+	knownImports := map[string]string{}
+	sections := []string{}
+	replaced := false
+	if content, err := ioutil.ReadFile(path); err == nil && isGeneratedSource(content) {
+		_, file := parseSource(string(content))
+		for _, spec := range file.Imports {
+			knownImports[getImportName(spec)] = getImportSpecSource(spec)
+		}
+		declared := getDeclaredTypes(body)
+		for _, s := range getSections(string(content)) {
+			sectionCommand := strings.TrimPrefix(s[:strings.IndexByte(s, '\n')], sectionBegin)
+			if sectionCommand == commandLine || isSameTypeSet(getDeclaredTypes(s), declared) {
+				if replaced {
+					continue
+				}
+				s, replaced = section, true
+			} else {
+				for typeName := range getDeclaredTypes(s) {
+					if declared[typeName] {
+						fatalf("-append type '%s' is already generated in %s by '%s'", typeName, path, sectionCommand)
+					}
+				}
+			}
+			sections = append(sections, s)
+		}
+	}
+	if !replaced {
+		sections = append(sections, section)
+	}
+
+	return getImportsSource(head+"\n\n"+strings.Join(sections, "\n"), knownImports)
+}
+
+// splitSourceBody - split the source src into its head, up to the package clause and the imports, and its body
+func splitSourceBody(src string) (string, string) {
+	fset, file := parseSource(src)
+	end := file.Name.End()
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			end = genDecl.End()
+		}
+	}
+	offset := fset.Position(end).Offset
+	return src[:offset], strings.TrimSpace(src[offset:])
+}
+
+// getSections - get the sections of the source src, from their begin line to their end line included
+func getSections(src string) []string {
+	sections := []string{}
+	section := ""
+	inSection := false
+	for _, line := range strings.Split(src, "\n") {
+		if strings.HasPrefix(line, sectionBegin) {
+			section, inSection = "", true
+		}
+		if inSection {
+			section += line + "\n"
+		}
+		if line == sectionEnd && inSection {
+			sections = append(sections, section)
+			inSection = false
+		}
+	}
+	return sections
+}
+
+// getDeclaredTypes - get the names of the types declared at the top level of the generated source src
+func getDeclaredTypes(src string) map[string]bool {
+	declared := map[string]bool{}
+	for _, line := range strings.Split(src, "\n") {
+		if fields := strings.Fields(line); len(fields) > 1 && strings.HasPrefix(line, "type ") {
+			declared[fields[1]] = true
+		}
+	}
+	return declared
+}
+
+// isSameTypeSet - whether the sets of type names a and b are the same
+func isSameTypeSet(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for typeName := range a {
+		if !b[typeName] {
+			return false
+		}
+	}
+	return true
+}
+
+// getImportName - get the name under which the package of the import spec is used
+func getImportName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	importPath, _ := strconv.Unquote(spec.Path.Value)
+	return path.Base(importPath)
+}
+
+// getImportSpecSource - get the source of the import spec
+func getImportSpecSource(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name + " " + spec.Path.Value
+	}
+	return spec.Path.Value
+}
@@ -0,0 +1,76 @@
+package fungen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// getMethodName - get the name of the method of the generator gen on a list, with the name targetTypeName of its
+// target type for the cross-type methods, in the naming style of -style, eg. 'MapString'
+func getMethodName(gen Generator, targetTypeName string) string {
+	return getStyledName(gen.Name) + getExportedName(strings.TrimPrefix(targetTypeName, "*"))
+}
+
+// getCollisions - get the descriptions of the collisions between the names generated for the types of typeMap, with
+// the methods in typeMethodsMaps, and for the pairs of pairList, with the -methods, which would not compile: the
+// types with the same name, the list types and pair structs with the same name and the methods of a list with the
+// same name, eg. 'MapA' for the target types named 'A' and 'a'. The cross-type methods which are not generated for the
+// type itself, like FilterMap, are left out
+func getCollisions(typeMap map[string]string, typeMethodsMaps map[string]map[string]bool, pairList [][2]string) []string {
+	collisions := []string{}
+	names := map[string]string{}
+	declared := map[string]string{}
+	declare := func(name, description string) {
+		if other, ok := declared[name]; ok {
+			collisions = append(collisions, fmt.Sprintf("%s and %s are both named '%s'", other, description, name))
+			return
+		}
+		declared[name] = description
+	}
+	checkMethods := func(typeName, listName string, m map[string]string, methodsMap map[string]bool) {
+		methodTargets := map[string]string{}
+		eachMethod(typeName, m, methodsMap, func(gen Generator, targetType, targetTypeName string) {
+			if gen.NeedMapToMap && targetTypeName == "" && strings.TrimSpace(getMethodSource(gen, listName, typeName, targetType, targetTypeName)) == "" {
+				return
+			}
+			name := getGeneratedMethodName(getMethodName(gen, targetTypeName))
+			if other, ok := methodTargets[name]; ok {
+				collisions = append(collisions, fmt.Sprintf("the methods %s and %s of the list type '%s' are both named '%s'", other, getMethodDescription(gen, targetType), listName, name))
+				return
+			}
+			methodTargets[name] = getMethodDescription(gen, targetType)
+		})
+	}
+
+	for _, k := range getSortedKeys(typeMap) {
+		name := getTypeName(k, typeMap)
+		if other, ok := names[name]; ok {
+			collisions = append(collisions, fmt.Sprintf("the types '%s' and '%s' are both named '%s'", other, k, name))
+		}
+		names[name] = k
+		declare(getListName(k, typeMap), fmt.Sprintf("the list type of '%s'", k))
+	}
+	for _, pair := range pairList {
+		pairName := getPairName(pair[0], pair[1], typeMap)
+		declare(pairName, fmt.Sprintf("the pair struct of '%s,%s'", pair[0], pair[1]))
+		declare(pairName+*listSuffix, fmt.Sprintf("the list type of the pair '%s,%s'", pair[0], pair[1]))
+	}
+
+	for _, k := range getSortedKeys(typeMap) {
+		checkMethods(k, getListName(k, typeMap), typeMap, typeMethodsMaps[k])
+	}
+	for _, pair := range pairList {
+		pairName := getPairName(pair[0], pair[1], typeMap)
+		checkMethods(pairName, pairName+*listSuffix, getPairMap(pairName, typeMap), getMethodsMap(*methods))
+	}
+	return collisions
+}
+
+// getMethodDescription - get the description of the method of the generator gen to the target type targetType in
+// the collisions, eg. Map to 'A'
+func getMethodDescription(gen Generator, targetType string) string {
+	if targetType == "" {
+		return gen.Name
+	}
+	return fmt.Sprintf("%s to '%s'", gen.Name, targetType)
+}
@@ -0,0 +1,95 @@
+package fungen
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+)
+
+// commands - the subcommands of fungen, by name, which are run with the arguments following their name. Without a
+// subcommand, the arguments are the flags of the generate command
+var commands = map[string]func(args []string){
+	"generate":     runGenerateCommand,
+	"check":        runCheckCommand,
+	"list-methods": runListMethodsCommand,
+	"version":      runVersionCommand,
+	"init":         runInitCommand,
+	"migrate":      runMigrateCommand,
+}
+
+// runGenerateCommand - generate the files according to the generation flags in args
+func runGenerateCommand(args []string) {
+	parseFlags(args)
+	run()
+}
+
+// runCheckCommand - check that the files generated according to the generation flags in args are up to date
+func runCheckCommand(args []string) {
+	parseFlags(args)
+	*check = true
+	run()
+}
+
+// methodInfo - the description of a method printed by list-methods -json
+type methodInfo struct {
+	Name      string   `json:"name"`
+	Requires  string   `json:"requires,omitempty"`
+	CrossType bool     `json:"crossType"`
+	OptIn     bool     `json:"optIn"`
+	ListType  bool     `json:"listType"`
+	Imports   []string `json:"imports"`
+}
+
+// runListMethodsCommand - print the names of the methods which can be generated, including the generators declared in
+// the configuration file, one per line, or their descriptions as JSON
+func runListMethodsCommand(args []string) {
+	flags := flag.NewFlagSet("list-methods", flag.ExitOnError)
+	optIn := flags.Bool("opt-in", false, "(Optional) Whether to only list the opt-in methods, which are not generated unless they are given in -methods.")
+	asJSON := flags.Bool("json", false, "(Optional) Whether to print the methods as a JSON array describing, for each method, the trait its types require, whether it is generated for each pair of types, whether it is opt-in, whether it needs a list type, which excludes it from -funcs, and the packages it imports.")
+	config := flags.String("config", "", "(Optional) Path of the configuration file declaring additional generators. By default '"+defaultConfigFile+"' is read if it exists.")
+	flags.Parse(args)
+	loadConfig(*config)
+
+	infos := []methodInfo{}
+	generators.Filter(func(gen Generator) bool {
+		return !*optIn || gen.OptIn
+	}).Each(func(gen Generator) {
+		infos = append(infos, getMethodInfo(gen))
+	})
+
+	if !*asJSON {
+		for _, info := range infos {
+			fmt.Println(info.Name)
+		}
+		return
+	}
+	out, err := json.MarshalIndent(infos, "", "  ")
+	if err != nil {
+		fatalf("encoding the methods as JSON: %s", err)
+	}
+	fmt.Println(string(out))
+}
+
+// getMethodInfo - get the description of the method generated by gen
+func getMethodInfo(gen Generator) methodInfo {
+	imports := append([]string{}, gen.Imports...)
+	if gen.NeedSync {
+		imports = append(imports, "sync")
+	}
+	return methodInfo{
+		Name:      gen.Name,
+		Requires:  gen.Requires,
+		CrossType: gen.NeedMapToMap,
+		OptIn:     gen.OptIn,
+		ListType:  gen.NeedListType,
+		Imports:   imports,
+	}
+}
+
+// runVersionCommand - print the version of fungen and the methods it supports
+func runVersionCommand(args []string) {
+	flags := flag.NewFlagSet("version", flag.ExitOnError)
+	flags.Parse(args)
+
+	fmt.Print(getVersionInfo())
+}
@@ -0,0 +1,193 @@
+package fungen
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// pathFlags - the flags whose values are paths, which are completed with the files
+var pathFlags = map[string]bool{
+	"filename":    true,
+	"outdir":      true,
+	"config":      true,
+	"header-file": true,
+	"templates":   true,
+	"cpuprofile":  true,
+	"memprofile":  true,
+}
+
+// methodsFlags - the flags whose values are comma-separated method names, which are completed with the names of the
+// generators
+var methodsFlags = map[string]bool{
+	"methods":         true,
+	"exclude-methods": true,
+}
+
+// completionScripts - the functions writing the completion script of each supported shell from the command names, the
+// flags and the method names
+var completionScripts = map[string]func(commandNames []string, flags []*flag.Flag, methodNames []string) string{
+	"bash": getBashCompletion,
+	"zsh":  getZshCompletion,
+	"fish": getFishCompletion,
+}
+
+// init - add the completion command to the commands, which it completes, outside of their declaration since it reads
+// them
+func init() {
+	commands["completion"] = runCompletionCommand
+}
+
+// runCompletionCommand - print the completion script of the shell given in args, bash, zsh or fish, for the commands,
+// the flags and the method names, including the generators declared in the configuration file. The command is not
+// listed in the usage
+func runCompletionCommand(args []string) {
+	flags := flag.NewFlagSet("completion", flag.ExitOnError)
+	config := flags.String("config", "", "(Optional) Path of the configuration file declaring additional generators. By default '"+defaultConfigFile+"' is read if it exists.")
+	flags.Parse(args)
+	if flags.NArg() != 1 || completionScripts[flags.Arg(0)] == nil {
+		fmt.Fprintf(os.Stderr, "Usage: fungen completion [-config file] bash|zsh|fish\n")
+		os.Exit(2)
+	}
+	loadConfig(*config)
+
+	commandNames := []string{}
+	for name := range commands {
+		if name != "completion" {
+			commandNames = append(commandNames, name)
+		}
+	}
+	sort.Strings(commandNames)
+	fungenFlags := []*flag.Flag{}
+	flagSet.VisitAll(func(f *flag.Flag) {
+		fungenFlags = append(fungenFlags, f)
+	})
+	methodNames := []string{}
+	generators.Each(func(gen Generator) {
+		methodNames = append(methodNames, gen.Name)
+	})
+
+	fmt.Print(completionScripts[flags.Arg(0)](commandNames, fungenFlags, methodNames))
+}
+
+// isBoolFlag - whether the flag f takes no value
+func isBoolFlag(f *flag.Flag) bool {
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
+
+// getFlagSummary - get the first sentence of the usage of the flag f, without the '(Optional)' prefix
+func getFlagSummary(f *flag.Flag) string {
+	summary := strings.TrimPrefix(f.Usage, "(Optional) ")
+	if i := strings.Index(summary, ". "); i >= 0 {
+		summary = summary[:i]
+	}
+	return strings.TrimSuffix(summary, ".")
+}
+
+// getBashCompletion - get the bash completion script, completing the commands, the flags and their values
+func getBashCompletion(commandNames []string, flags []*flag.Flag, methodNames []string) string {
+	flagNames, pathNames, methodsNames, valueNames := []string{}, []string{}, []string{}, []string{}
+	for _, f := range flags {
+		flagNames = append(flagNames, "-"+f.Name)
+		if pathFlags[f.Name] {
+			pathNames = append(pathNames, "-"+f.Name, "--"+f.Name)
+		} else if methodsFlags[f.Name] {
+			methodsNames = append(methodsNames, "-"+f.Name, "--"+f.Name)
+		} else if !isBoolFlag(f) {
+			valueNames = append(valueNames, "-"+f.Name, "--"+f.Name)
+		}
+	}
+
+	return fmt.Sprintf(`# bash completion for fungen, generated by 'fungen completion bash'
+_fungen() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    case "$prev" in
+    %[3]s)
+        local prefix="${cur%%${cur##*,}}"
+        COMPREPLY=($(compgen -P "$prefix" -W "%[4]s" -- "${cur##*,}"))
+        compopt -o nospace 2>/dev/null
+        return
+        ;;
+    %[5]s)
+        COMPREPLY=($(compgen -f -- "$cur"))
+        return
+        ;;
+    %[6]s)
+        return
+        ;;
+    esac
+    if [[ $COMP_CWORD -eq 1 && $cur != -* ]]; then
+        COMPREPLY=($(compgen -W "%[1]s" -- "$cur"))
+        return
+    fi
+    COMPREPLY=($(compgen -W "%[2]s" -- "$cur"))
+}
+complete -F _fungen fungen
+`, strings.Join(commandNames, " "), strings.Join(flagNames, " "), strings.Join(methodsNames, "|"), strings.Join(methodNames, " "), strings.Join(pathNames, "|"), strings.Join(valueNames, "|"))
+}
+
+// getZshCompletion - get the zsh completion script, completing the commands, the flags, with their summaries, and
+// their values
+func getZshCompletion(commandNames []string, flags []*flag.Flag, methodNames []string) string {
+	specs := []string{}
+	for _, f := range flags {
+		summary := strings.NewReplacer("'", `'\''`, "[", `\[`, "]", `\]`, ":", `\:`).Replace(getFlagSummary(f))
+		spec := fmt.Sprintf("-%s[%s]", f.Name, summary)
+		if pathFlags[f.Name] {
+			spec += ":path:_files"
+		} else if methodsFlags[f.Name] {
+			spec += ":methods:_sequence compadd - $methods"
+		} else if !isBoolFlag(f) {
+			spec += ":value: "
+		}
+		specs = append(specs, "'"+spec+"'")
+	}
+
+	return fmt.Sprintf(`#compdef fungen
+# zsh completion for fungen, generated by 'fungen completion zsh'
+_fungen() {
+    local -a methods
+    methods=(%[3]s)
+    _arguments \
+        '1::command:(%[1]s)' \
+        %[2]s
+}
+if [ "$funcstack[1]" = "_fungen" ]; then
+    _fungen "$@"
+else
+    compdef _fungen fungen
+fi
+`, strings.Join(commandNames, " "), strings.Join(specs, " \\\n        "), strings.Join(methodNames, " "))
+}
+
+// getFishCompletion - get the fish completion script, completing the commands, the flags, with their summaries, and
+// their values
+func getFishCompletion(commandNames []string, flags []*flag.Flag, methodNames []string) string {
+	lines := []string{}
+	for _, f := range flags {
+		line := fmt.Sprintf("complete -c fungen -o %s -d '%s'", f.Name, strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(getFlagSummary(f)))
+		if pathFlags[f.Name] {
+			line += " -r -F"
+		} else if methodsFlags[f.Name] {
+			line += " -x -a '(__fungen_methods)'"
+		} else if !isBoolFlag(f) {
+			line += " -x"
+		}
+		lines = append(lines, line)
+	}
+
+	return fmt.Sprintf(`# fish completion for fungen, generated by 'fungen completion fish'
+function __fungen_methods
+    set -l prefix (string replace -r '[^,]*$' '' -- (commandline -ct))
+    for method in %[3]s
+        echo $prefix$method
+    end
+end
+complete -c fungen -f
+complete -c fungen -n '__fish_use_subcommand' -a '%[1]s'
+%[2]s
+`, strings.Join(commandNames, " "), strings.Join(lines, "\n"), strings.Join(methodNames, " "))
+}
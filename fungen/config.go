@@ -0,0 +1,279 @@
+package fungen
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// defaultConfigFile - the configuration file read from the current directory when -config is not given
+const defaultConfigFile = ".fungen.json"
+
+// config - the contents of a configuration file. The options are named after the command line flags. The packages
+// are generated each in their directory, relative to the configuration file, with the other values as defaults
+type config struct {
+	Dir        string                 `json:"dir,omitempty"`
+	Package    string                 `json:"package,omitempty"`
+	Output     string                 `json:"output,omitempty"`
+	Methods    []string               `json:"methods,omitempty"`
+	Types      []configType           `json:"types,omitempty"`
+	Pairs      [][2]string            `json:"pairs,omitempty"`
+	Options    map[string]interface{} `json:"options,omitempty"`
+	Generators []configGenerator      `json:"generators,omitempty"`
+	Packages   []config               `json:"packages,omitempty"`
+}
+
+// configType - a type declared in a configuration file, with its optional name, list type name, methods and traits
+type configType struct {
+	Type    string   `json:"type"`
+	Name    string   `json:"name,omitempty"`
+	List    string   `json:"list,omitempty"`
+	Methods []string `json:"methods,omitempty"`
+	Traits  []string `json:"traits,omitempty"`
+}
+
+// configGenerator - a method generator declared in a configuration file, whose template is given either inline or as
+// the path of a file relative to the configuration file, along with the information describing how it is generated
+type configGenerator struct {
+	Name         string   `json:"name"`
+	Template     string   `json:"template,omitempty"`
+	TemplateFile string   `json:"templateFile,omitempty"`
+	CrossType    bool     `json:"crossType,omitempty"`
+	OptIn        bool     `json:"optIn,omitempty"`
+	Imports      []string `json:"imports,omitempty"`
+	Requires     string   `json:"requires,omitempty"`
+}
+
+// loadConfig - read the configuration file, add its generators and apply its values to the flags which were not set on
+// the command line, and return its packages, if it has any, with their defaults. A missing default configuration file
+// is ignored
+func loadConfig(path string) []config {
+	if path == "" {
+		path = defaultConfigFile
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil
+		}
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		fatalf("reading config file '%s': %s", path, err)
+	}
+
+	c := config{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&c); err != nil {
+		fatalf("config file '%s' is not valid%s", path, getConfigErrorDetails(content, err))
+	}
+
+	for _, g := range c.Generators {
+		text := g.Template
+		if g.TemplateFile != "" {
+			content, err := ioutil.ReadFile(filepath.Join(filepath.Dir(path), g.TemplateFile))
+			if err != nil {
+				fatalf("config file '%s' generator '%s': %s", path, g.Name, err)
+			}
+			text = string(content)
+		}
+		addTemplateGenerator(g.Name, text, g.CrossType, g.OptIn, g.Imports, g.Requires)
+	}
+
+	if len(c.Packages) > 0 {
+		return getPackageConfigs(path, c)
+	}
+	applyConfig(path, c)
+	return nil
+}
+
+// applyConfig - apply the values of the configuration c, read from path, to the flags which were not set on the
+// command line
+func applyConfig(path string, c config) {
+	setFlags := map[string]bool{}
+	flagSet.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+	setFlag := func(name, value string) {
+		if setFlags[name] || value == "" {
+			return
+		}
+		if err := flagSet.Set(name, value); err != nil {
+			fatalf("config file '%s' option '%s' is not valid: %s", path, name, err)
+		}
+	}
+
+	setFlag("package", c.Package)
+	setFlag("filename", c.Output)
+	setFlag("methods", strings.Join(c.Methods, ","))
+	for name, value := range c.Options {
+		if flagSet.Lookup(name) == nil {
+			fatalf("config file '%s' option '%s' is not a fungen flag%s", path, name, didYouMean(name, getFlagNames()))
+		}
+		setFlag(name, fmt.Sprint(value))
+	}
+
+	pairsStr := []string{}
+	for _, pair := range c.Pairs {
+		pairsStr = append(pairsStr, "'"+pair[0]+"','"+pair[1]+"'")
+	}
+	setFlag("pairs", strings.Join(pairsStr, ";"))
+
+	if setFlags["types"] {
+		return
+	}
+	typesStr := []string{}
+	for _, t := range c.Types {
+		typesStr = append(typesStr, "'"+t.Type+"':"+t.Name+":"+t.List)
+		if len(t.Methods) > 0 {
+			typeMethods[t.Type] = strings.Join(t.Methods, ",")
+		}
+		if len(t.Traits) > 0 {
+			addTypeTraits(t.Type, t.Traits)
+		}
+	}
+	setFlag("types", strings.Join(typesStr, ","))
+}
+
+// getPackageConfigs - get the configurations of the packages of c, read from path, with their directories relative to
+// the current directory, their output files in their directories and the methods, output file name and options of c
+// as defaults. The package names default to the names of the packages in the directories or to the directory names
+func getPackageConfigs(path string, c config) []config {
+	packages := []config{}
+	for _, p := range c.Packages {
+		if p.Dir == "" {
+			fatalf("config file '%s' has a package without dir", path)
+		}
+		if len(p.Packages) > 0 || len(p.Generators) > 0 {
+			fatalf("config file '%s' package '%s' cannot declare packages or generators, they are declared at the top level", path, p.Dir)
+		}
+		p.Dir = filepath.Join(filepath.Dir(path), p.Dir)
+		if len(p.Methods) == 0 {
+			p.Methods = c.Methods
+		}
+		output := p.Output
+		if output == "" {
+			output = filepath.Base(c.Output)
+		}
+		if output == "" || output == "." {
+			output = "fungen_auto.go"
+		}
+		p.Output = filepath.Join(p.Dir, output)
+		options := map[string]interface{}{}
+		for name, value := range c.Options {
+			options[name] = value
+		}
+		for name, value := range p.Options {
+			options[name] = value
+		}
+		p.Options = options
+		if p.Package == "" {
+			p.Package = getDirPackageName(p.Dir)
+		}
+		packages = append(packages, p)
+	}
+	return packages
+}
+
+// runPackages - generate the packages of the configuration file, each with the flags set on the command line and its
+// configuration
+func runPackages(packages []config) {
+	path := *configFile
+	if path == "" {
+		path = defaultConfigFile
+	}
+	// the flags set on the command line, or by the command, are set again for each package once they are reset
+	values := getChangedFlags()
+
+	for _, p := range packages {
+		resetState()
+		flagSet.Usage = usage
+		for name, value := range values {
+			flagSet.Set(name, value)
+		}
+		// the package is generated again from its directory with the configuration file, which generates all the
+		// packages
+		if rel, err := filepath.Rel(p.Dir, path); err == nil {
+			flagSet.Set("config", filepath.ToSlash(rel))
+		}
+		commandLine = getCommandLine(flagSet)
+		applyConfig(path, p)
+		verbosef("generating the package %s in %s", p.Package, p.Dir)
+		generateAndWrite()
+	}
+}
+
+// getDirPackageName - get the name of the package in dir, or the name of dir if it has no Go files
+func getDirPackageName(dir string) string {
+	if files := parsePackageFiles(token.NewFileSet(), dir, parser.PackageClauseOnly); len(files) > 0 {
+		return files[0].Name.Name
+	}
+	return filepath.Base(dir)
+}
+
+// unknownFieldPattern - the pattern of the errors of the JSON decoder for the fields which are not declared
+var unknownFieldPattern = regexp.MustCompile(`^json: unknown field "(.*)"$`)
+
+// getConfigErrorDetails - get the details of the error err decoding the configuration file content, appended to the
+// error: the line and column of the syntax and type errors along with the line pointed out, or the suggestion of the
+// closest field for the unknown fields
+func getConfigErrorDetails(content []byte, err error) string {
+	offset := int64(-1)
+	switch err := err.(type) {
+	case *json.SyntaxError:
+		offset = err.Offset
+	case *json.UnmarshalTypeError:
+		offset = err.Offset
+	}
+	if match := unknownFieldPattern.FindStringSubmatch(err.Error()); match != nil {
+		return ": " + err.Error() + didYouMean(match[1], getConfigFieldNames(reflect.TypeOf(config{})))
+	}
+	if offset < 0 || offset > int64(len(content)) {
+		return ": " + err.Error()
+	}
+
+	lineStart := bytes.LastIndexByte(content[:offset], '\n') + 1
+	lineEnd := bytes.IndexByte(content[offset:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(content)
+	} else {
+		lineEnd += int(offset)
+	}
+	line := bytes.Count(content[:offset], []byte("\n")) + 1
+	column := int(offset) - lineStart
+	if column > 0 {
+		column--
+	}
+	return fmt.Sprintf(" at line %d, column %d: %s%s", line, column+1, err, getTokenPointer(string(content[lineStart:lineEnd]), column, column+1))
+}
+
+// getConfigFieldNames - get the JSON names of the fields of the struct type t and of the structs it contains, which
+// are the suggestions for the unknown fields of the configuration file
+func getConfigFieldNames(t reflect.Type) []string {
+	names := []string{}
+	seen := map[reflect.Type]bool{}
+	var add func(t reflect.Type)
+	add = func(t reflect.Type) {
+		for t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || seen[t] {
+			return
+		}
+		seen[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			names = append(names, strings.Split(t.Field(i).Tag.Get("json"), ",")[0])
+			add(t.Field(i).Type)
+		}
+	}
+	add(t)
+	return names
+}
@@ -0,0 +1,207 @@
+package fungen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// conflictStrategies - the strategies of -on-conflict for the generated identifiers which are already declared in the
+// target package: reporting them, or renaming them with a number suffix, eg. 'intList2'
+var conflictStrategies = map[string]bool{
+	"error":  true,
+	"suffix": true,
+}
+
+// fileDecls - the package-level identifiers declared in files, with the position of their declaration, and the
+// methods declared on each type, with the position of their declaration
+type fileDecls struct {
+	names   map[string]token.Position
+	methods map[string]map[string]token.Position
+}
+
+// addFileDecls - add the package-level identifiers and the methods declared in file, parsed in fset, to decls
+func addFileDecls(decls fileDecls, fset *token.FileSet, file *ast.File) {
+	add := func(ident *ast.Ident) {
+		if ident.Name != "_" && ident.Name != "init" {
+			decls.names[ident.Name] = fset.Position(ident.Pos())
+		}
+	}
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				add(decl.Name)
+				continue
+			}
+			recvName := getReceiverListName(decl)
+			if decls.methods[recvName] == nil {
+				decls.methods[recvName] = map[string]token.Position{}
+			}
+			decls.methods[recvName][decl.Name.Name] = fset.Position(decl.Name.Pos())
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					add(spec.Name)
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						add(name)
+					}
+				}
+			}
+		}
+	}
+}
+
+// getPackageDecls - get the identifiers and the methods declared in the files of the target package, which do not
+// include the files generated by fungen
+func getPackageDecls() fileDecls {
+	loadPackageFiles()
+	decls := fileDecls{map[string]token.Position{}, map[string]map[string]token.Position{}}
+	for _, file := range packageFiles {
+		addFileDecls(decls, packageFset, file)
+	}
+	return decls
+}
+
+// resolveConflicts - check that the identifiers declared in the generated files, by file name, are not already
+// declared in the target package, and that the methods generated on its types, ie. the discovered lists, are not
+// already declared on them. The conflicting identifiers are reported, or renamed in all the files, including the test
+// files, with -on-conflict suffix. The conflicting methods are always reported, since renaming them would change the
+// API of the lists
+func resolveConflicts(files map[string]string) {
+	if *subpackage != "" {
+		// the generated subpackage only has the generated files, its identifiers cannot conflict with the target package
+		return
+	}
+	pkgDecls := getPackageDecls()
+	if len(pkgDecls.names) == 0 && len(pkgDecls.methods) == 0 {
+		return
+	}
+
+	fileNames := []string{}
+	fileDeclsByName := map[string]fileDecls{}
+	generated := fileDecls{map[string]token.Position{}, map[string]map[string]token.Position{}}
+	for _, fileName := range getSortedKeys(files) {
+		if strings.HasSuffix(fileName, "_test.go") {
+			continue
+		}
+		fset, file := parseSource(files[fileName])
+		decls := fileDecls{map[string]token.Position{}, map[string]map[string]token.Position{}}
+		addFileDecls(decls, fset, file)
+		addFileDecls(generated, fset, file)
+		fileNames = append(fileNames, fileName)
+		fileDeclsByName[fileName] = decls
+	}
+
+	conflicts, methodConflicts := []string{}, []string{}
+	renames := map[string]string{}
+	for _, fileName := range fileNames {
+		for name := range fileDeclsByName[fileName].names {
+			if declared, ok := pkgDecls.names[name]; ok {
+				conflicts = append(conflicts, fmt.Sprintf("'%s', generated in %s, is already declared at %s", name, getOutputPath(fileName), declared))
+				renames[name] = ""
+			}
+		}
+	}
+	for _, fileName := range fileNames {
+		for recvName, methods := range fileDeclsByName[fileName].methods {
+			if _, renamed := renames[recvName]; renamed {
+				continue
+			}
+			for name := range methods {
+				if declared, ok := pkgDecls.methods[recvName][name]; ok {
+					methodConflicts = append(methodConflicts, fmt.Sprintf("the method '%s.%s', generated in %s, is already declared at %s", recvName, name, getOutputPath(fileName), declared))
+				}
+			}
+		}
+	}
+	sort.Strings(conflicts)
+	sort.Strings(methodConflicts)
+
+	if *onConflict == "error" && len(conflicts) > 0 || len(methodConflicts) > 0 {
+		fatalf("the generated identifiers conflict with the target package, give the types other names in -types or, for the types and functions, use -on-conflict suffix:\n%s", strings.Join(append(conflicts, methodConflicts...), "\n"))
+	}
+
+	for _, name := range getSortedKeys(renames) {
+		for i := 2; ; i++ {
+			renamed := name + strconv.Itoa(i)
+			if _, declared := pkgDecls.names[renamed]; !declared {
+				if _, taken := generated.names[renamed]; !taken {
+					renames[name] = renamed
+					break
+				}
+			}
+		}
+		warnf("'%s' is already declared at %s, it is generated as '%s'", name, pkgDecls.names[name], renames[name])
+	}
+	for fileName, src := range files {
+		files[fileName] = getRenamedSource(src, renames)
+	}
+}
+
+// getRenamedSource - rename the generated package-level identifiers referenced in src according to renames, along with
+// the words of the comments naming them. Selected names, method names, fields, keys of composite literals and the
+// package comment, with the command line, are left as they are
+func getRenamedSource(src string, renames map[string]string) string {
+	if len(renames) == 0 {
+		return src
+	}
+	fset, file := parseSource(src)
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	edits := []edit{}
+	inspectWithParent(file, func(n, parent ast.Node) {
+		ident, ok := n.(*ast.Ident)
+		if !ok || renames[ident.Name] == "" || ident.Obj != nil && ident.Obj != file.Scope.Lookup(ident.Name) {
+			return
+		}
+		switch parent := parent.(type) {
+		case *ast.SelectorExpr:
+			if parent.Sel == ident {
+				return
+			}
+		case *ast.FuncDecl:
+			if parent.Recv != nil && parent.Name == ident {
+				return
+			}
+		case *ast.KeyValueExpr:
+			if parent.Key == ident {
+				return
+			}
+		case *ast.Field:
+			for _, name := range parent.Names {
+				if name == ident {
+					return
+				}
+			}
+		}
+		edits = append(edits, edit{offset(ident.Pos()), offset(ident.End()), renames[ident.Name]})
+	})
+
+	names := []string{}
+	for name := range renames {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	words := regexp.MustCompile(`\b(` + strings.Join(names, "|") + `)\b`)
+	for _, group := range file.Comments {
+		if group == file.Doc {
+			continue
+		}
+		for _, comment := range group.List {
+			for _, loc := range words.FindAllStringIndex(comment.Text, -1) {
+				start := offset(comment.Slash) + loc[0]
+				edits = append(edits, edit{start, offset(comment.Slash) + loc[1], renames[comment.Text[loc[0]:loc[1]]]})
+			}
+		}
+	}
+
+	return f(applyEdits(src, edits))
+}
@@ -0,0 +1,155 @@
+package fungen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext - the number of unchanged lines shown around the changes in a diff
+const diffContext = 3
+
+// maxDiffCells - the maximum size of the table used to find the longest common subsequence of the changed lines,
+// beyond which they are all shown as replaced
+const maxDiffCells = 10000000
+
+// diffLine - a line of a diff, whose kind is ' ' if it is unchanged, '-' if it is removed and '+' if it is added
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// getUnifiedDiff - get the unified diff from the text before, in the file oldName, to the text after, in the file
+// newName, or "" if they are the same
+func getUnifiedDiff(oldName, newName, before, after string) string {
+	if before == after {
+		return ""
+	}
+	lines := getDiffLines(splitLines(before), splitLines(after))
+
+	// the number of lines of each text before each line of the diff
+	oldCounts, newCounts := make([]int, len(lines)+1), make([]int, len(lines)+1)
+	for i, line := range lines {
+		oldCounts[i+1], newCounts[i+1] = oldCounts[i], newCounts[i]
+		if line.kind != '+' {
+			oldCounts[i+1]++
+		}
+		if line.kind != '-' {
+			newCounts[i+1]++
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString("--- " + oldName + "\n+++ " + newName + "\n")
+	for i := 0; i < len(lines); {
+		if lines[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		start, last := i-diffContext, i
+		if start < 0 {
+			start = 0
+		}
+		for j := i; j < len(lines) && j-last <= 2*diffContext; j++ {
+			if lines[j].kind != ' ' {
+				last = j
+			}
+		}
+		end := last + diffContext + 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		fmt.Fprintf(&result, "@@ -%s +%s @@\n", getHunkRange(oldCounts[start], oldCounts[end]), getHunkRange(newCounts[start], newCounts[end]))
+		for _, line := range lines[start:end] {
+			result.WriteString(string(line.kind) + line.text + "\n")
+		}
+		i = end
+	}
+	return result.String()
+}
+
+// getHunkRange - get the range of the lines of a hunk for its header, from the number of lines before it and the
+// number of lines up to its end
+func getHunkRange(before, end int) string {
+	if end-before == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if end-before == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, end-before)
+}
+
+// splitLines - split text into lines, without the last empty line if text ends with a newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// getDiffLines - get the lines of the diff from the lines a to the lines b, made of their longest common subsequence
+// of unchanged lines and of the removed and added lines
+func getDiffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := []diffLine{}
+	for _, text := range a[:prefix] {
+		lines = append(lines, diffLine{' ', text})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > maxDiffCells {
+		for _, text := range midA {
+			lines = append(lines, diffLine{'-', text})
+		}
+		for _, text := range midB {
+			lines = append(lines, diffLine{'+', text})
+		}
+	} else {
+		// lcs[i][j] is the length of the longest common subsequence of midA[i:] and midB[j:]
+		lcs := make([][]int, len(midA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(midB)+1)
+		}
+		for i := len(midA) - 1; i >= 0; i-- {
+			for j := len(midB) - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+
+		i, j := 0, 0
+		for i < len(midA) || j < len(midB) {
+			switch {
+			case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+				lines = append(lines, diffLine{' ', midA[i]})
+				i, j = i+1, j+1
+			case j == len(midB) || i < len(midA) && lcs[i+1][j] >= lcs[i][j+1]:
+				lines = append(lines, diffLine{'-', midA[i]})
+				i++
+			default:
+				lines = append(lines, diffLine{'+', midB[j]})
+				j++
+			}
+		}
+	}
+
+	for _, text := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{' ', text})
+	}
+	return lines
+}
@@ -0,0 +1,111 @@
+package fungen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"strings"
+)
+
+// directivePrefix - the prefix of the comments configuring the generation for the type declarations they document
+const directivePrefix = "//fungen:"
+
+// loadDirectives - get the -types value from the directive comments documenting the type declarations of the
+// package in dir, eg. '//fungen:methods=Map,Filter alias=U', and record the methods and traits they select
+func loadDirectives(dir string) string {
+	fset := token.NewFileSet()
+	typesStr := []string{}
+	for _, file := range parsePackageFiles(fset, dir, parser.ParseComments) {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				doc := typeSpec.Doc
+				if doc == nil && !genDecl.Lparen.IsValid() {
+					doc = genDecl.Doc
+				}
+				if doc == nil {
+					continue
+				}
+				for _, c := range doc.List {
+					if strings.HasPrefix(c.Text, directivePrefix) {
+						typesStr = append(typesStr, getDirectiveType(typeSpec.Name.Name, c.Text, fset.Position(c.Pos())))
+					}
+				}
+			}
+		}
+	}
+	return strings.Join(typesStr, ",")
+}
+
+// getDirectiveType - get the -types entry for the type typeName from its directive comment, whose space separated
+// key=value settings are methods, alias, list and traits, eg. '//fungen:methods=Map,Filter alias=U traits=comparable'
+func getDirectiveType(typeName, directive string, pos token.Position) string {
+	alias, list := "", ""
+	for _, setting := range strings.Fields(strings.TrimPrefix(directive, directivePrefix)) {
+		sParts := strings.SplitN(setting, "=", 2)
+		if len(sParts) != 2 {
+			fatalf("%s: fungen directive setting '%s' is not of the form key=value", pos, setting)
+		}
+		switch value := sParts[1]; sParts[0] {
+		case "methods":
+			typeMethods[typeName] = value
+		case "alias":
+			alias = value
+		case "list":
+			list = value
+		case "traits":
+			addTypeTraits(typeName, strings.Split(value, ","))
+		default:
+			fatalf("%s: fungen directive setting '%s' is not one of methods, alias, list and traits", pos, sParts[0])
+		}
+	}
+	return typeName + ":" + alias + ":" + list
+}
+
+// discoverLists - get the -types entries for the named slice types declared in the package in dir, eg. 'User::Users'
+// for 'type Users []User', and record them as existing so that they are not declared again
+func discoverLists(dir string) []string {
+	typesStr := []string{}
+	for _, list := range discoverSliceTypes(dir) {
+		existLists[list[1]] = true
+		typesStr = append(typesStr, "'"+list[0]+"'::"+list[1])
+	}
+	return typesStr
+}
+
+// discoverSliceTypes - get the named slice types declared in the package in dir, as their member types along with
+// their names, eg. {"User", "Users"} for 'type Users []User'. Only the first slice type of each member type is kept and
+// the generic ones, eg. 'type Set[T comparable] []T', are left out
+func discoverSliceTypes(dir string) [][2]string {
+	fset := token.NewFileSet()
+	lists := [][2]string{}
+	elemLists := map[string]string{}
+	for _, file := range parsePackageFiles(fset, dir, 0) {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				arrayType, ok := typeSpec.Type.(*ast.ArrayType)
+				if !ok || arrayType.Len != nil || typeSpec.Assign.IsValid() || typeSpec.TypeParams != nil {
+					continue
+				}
+				elem, listName := gotypes.ExprString(arrayType.Elt), typeSpec.Name.Name
+				if other, ok := elemLists[elem]; ok {
+					warnf("skipping %s, the list of %s is already %s", listName, elem, other)
+					continue
+				}
+				elemLists[elem] = listName
+				lists = append(lists, [2]string{elem, listName})
+			}
+		}
+	}
+	return lists
+}
@@ -0,0 +1,129 @@
+package fungen
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+)
+
+// docType - a type declared in the generated files, with the short description of its doc comment and the
+// descriptions of its methods, in the order of their declaration
+type docType struct {
+	name        string
+	description string
+	methods     [][2]string
+}
+
+// getDocFileName - get the name of the file summarizing the API generated in the file fileName, eg. 'fungen_auto_doc.go'
+func getDocFileName(fileName string) string {
+	return strings.TrimSuffix(fileName, ".go") + "_doc.go"
+}
+
+// getFirstSentence - get the first sentence of the doc comment doc, on a single line and without its period
+func getFirstSentence(doc *ast.CommentGroup) string {
+	text := strings.Join(strings.Fields(doc.Text()), " ")
+	if i := strings.Index(text, ". "); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSuffix(text, ".")
+}
+
+// getShortDescription - get the first sentence of the doc comment doc of the function or method name, without the
+// name and the receiver it starts with, eg. 'takes a function of type int -> bool ...' for 'Filter is a method on
+// intList that takes a function of type int -> bool ...'
+func getShortDescription(name string, doc *ast.CommentGroup) string {
+	text := strings.TrimPrefix(getFirstSentence(doc), name+" ")
+	if strings.HasPrefix(text, "is a method on ") || strings.HasPrefix(text, "is a function on ") {
+		if i := strings.Index(text, " that "); i >= 0 {
+			text = text[i+len(" that "):]
+		}
+	}
+	text = strings.TrimPrefix(text, "- ")
+	return strings.TrimPrefix(text, "is ")
+}
+
+// getDocTypes - get the exported types declared in the generated files, except the test files, with their methods,
+// sorted by name, and the exported functions declared in them, with their descriptions. The unexported types are left
+// out since their methods are not in the documentation of the package
+func getDocTypes(files map[string]string) ([]docType, [][2]string) {
+	typesByName := map[string]*docType{}
+	funcs := [][2]string{}
+	methods := map[string][][2]string{}
+	for _, fileName := range getSortedKeys(files) {
+		if strings.HasSuffix(fileName, "_test.go") {
+			continue
+		}
+		_, file := parseSource(files[fileName])
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if !decl.Name.IsExported() {
+					continue
+				}
+				description := [2]string{decl.Name.Name, getShortDescription(decl.Name.Name, decl.Doc)}
+				if decl.Recv == nil {
+					funcs = append(funcs, description)
+				} else if recvName := getReceiverListName(decl); recvName != "" {
+					methods[recvName] = append(methods[recvName], description)
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok {
+						doc := spec.Doc
+						if doc == nil {
+							doc = decl.Doc
+						}
+						typesByName[spec.Name.Name] = &docType{name: spec.Name.Name, description: getFirstSentence(doc)}
+					}
+				}
+			}
+		}
+	}
+
+	docTypes := []docType{}
+	for name, t := range typesByName {
+		t.methods = methods[name]
+		if ast.IsExported(name) {
+			docTypes = append(docTypes, *t)
+		}
+	}
+	sort.Slice(docTypes, func(i, j int) bool { return docTypes[i].name < docTypes[j].name })
+	return docTypes, funcs
+}
+
+// generateDocSource - generate the source of the file with the package comment summarizing the types, methods and
+// functions declared in the generated files, so that the documentation of the package gives an overview of them
+func generateDocSource(files map[string]string) string {
+	docTypes, funcs := getDocTypes(files)
+
+	lines := []string{"// Package " + *packageName + " - " + generatedMarker, "//", "// # Generated API", "//", "// The types and functions generated by fungen in this package:"}
+	addItem := func(link string, description [2]string) {
+		item := "//   - [" + link + "]"
+		if description[1] != "" {
+			item += ": " + description[1]
+		}
+		lines = append(lines, item)
+	}
+	for _, t := range docTypes {
+		lines = append(lines, "//", "// # "+t.name, "//")
+		if t.description != "" {
+			lines = append(lines, "// "+t.description+".", "//")
+		}
+		if len(t.methods) == 0 {
+			lines = append(lines, "// It has no generated methods.")
+			continue
+		}
+		lines = append(lines, "// Its generated methods:", "//")
+		for _, method := range t.methods {
+			addItem(t.name+"."+method[0], method)
+		}
+	}
+	if len(funcs) > 0 {
+		lines = append(lines, "//", "// # Functions", "//")
+		for _, fn := range funcs {
+			addItem(fn[0], fn)
+		}
+	}
+
+	return f(getFileHeader(*headerFile) + getBuildConstraint(*buildTags) + strings.Join(lines, "\n") + "\npackage " + *packageName + "\n")
+}
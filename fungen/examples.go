@@ -0,0 +1,545 @@
+package fungen
+
+import "text/template"
+
+// exampleList - the template shared by the example templates declaring the list l of the example, whose members are
+// distinct for the numeric types and the zero value of the type, zero, otherwise
+const exampleList = `{{define "list"}}
+        {{- if .Numeric}}
+        l := {{.List}}{3, 1, 2}
+        {{- else}}
+        var zero {{.Type}}
+        l := {{.List}}{zero, zero, zero}
+        {{- end}}{{end}}`
+
+// parseExampleTemplate - parse the example template text of the method name. The examples are only generated for
+// the exported list types, since the others are not shown in godoc
+func parseExampleTemplate(name, text string) *template.Template {
+	return parseTestTemplate(name, exampleList+"{{if .Exported}}"+text+"{{end}}")
+}
+
+// exampleTemplates - the templates of the examples of the built-in methods, by method name
+var exampleTemplates = map[string]*template.Template{
+	"Map":    parseExampleTemplate("Map", mapExampleText),
+	"PMap":   parseExampleTemplate("PMap", mapExampleText),
+	"Filter": parseExampleTemplate("Filter", filterExampleText),
+	"PFilter": parseExampleTemplate("PFilter", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            fmt.Println(len(l.{{.Method}}(func(v {{.Type}}) bool { return v > 1 })))
+            // Output: 2
+            {{else}}
+            fmt.Println(len(l.{{.Method}}(func({{.Type}}) bool { return true })))
+            // Output: 3
+            {{end}}
+        }
+        `),
+	"MapInPlace":  parseExampleTemplate("MapInPlace", mapExampleText),
+	"Reduce":      parseExampleTemplate("Reduce", reduceExampleText),
+	"ReduceRight": parseExampleTemplate("ReduceRight", reduceExampleText),
+	"Take":        parseExampleTemplate("Take", takeExampleText),
+	"Truncate":    parseExampleTemplate("Truncate", takeExampleText),
+	"Drop": parseExampleTemplate("Drop", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            fmt.Println([]{{.Type}}(l.{{.Method}}(1)))
+            // Output: [1 2]
+            {{else}}
+            fmt.Println(len(l.{{.Method}}(1)))
+            // Output: 2
+            {{end}}
+        }
+        `),
+	"TakeWhile": parseExampleTemplate("TakeWhile", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            fmt.Println([]{{.Type}}(l.{{.Method}}(func(v {{.Type}}) bool { return v > 2 })))
+            // Output: [3]
+            {{else}}
+            fmt.Println(len(l.{{.Method}}(func({{.Type}}) bool { return true })))
+            // Output: 3
+            {{end}}
+        }
+        `),
+	"DropWhile": parseExampleTemplate("DropWhile", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            fmt.Println([]{{.Type}}(l.{{.Method}}(func(v {{.Type}}) bool { return v > 2 })))
+            // Output: [1 2]
+            {{else}}
+            fmt.Println(len(l.{{.Method}}(func({{.Type}}) bool { return true })))
+            // Output: 0
+            {{end}}
+        }
+        `),
+	"Each": parseExampleTemplate("Each", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            l.{{.Method}}(func(v {{.Type}}) {
+                fmt.Println(v)
+            })
+            // Output:
+            // 3
+            // 1
+            // 2
+            {{else}}
+            n := 0
+            l.{{.Method}}(func({{.Type}}) {
+                n++
+            })
+            fmt.Println(n)
+            // Output: 3
+            {{end}}
+        }
+        `),
+	"EachI": parseExampleTemplate("EachI", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            l.{{.Method}}(func(i int, v {{.Type}}) {
+                fmt.Println(i, v)
+            })
+            // Output:
+            // 0 3
+            // 1 1
+            // 2 2
+            {{else}}
+            l.{{.Method}}(func(i int, _ {{.Type}}) {
+                fmt.Println(i)
+            })
+            // Output:
+            // 0
+            // 1
+            // 2
+            {{end}}
+        }
+        `),
+	"All": parseExampleTemplate("All", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            fmt.Println(l.{{.Method}}(func(v {{.Type}}) bool { return v > 0 }), l.{{.Method}}(func(v {{.Type}}) bool { return v > 1 }))
+            {{else}}
+            fmt.Println(l.{{.Method}}(func({{.Type}}) bool { return true }), l.{{.Method}}(func({{.Type}}) bool { return false }))
+            {{end}}
+            // Output: true false
+        }
+        `),
+	"Any": parseExampleTemplate("Any", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            fmt.Println(l.{{.Method}}(func(v {{.Type}}) bool { return v > 2 }), l.{{.Method}}(func(v {{.Type}}) bool { return v > 3 }))
+            {{else}}
+            fmt.Println(l.{{.Method}}(func({{.Type}}) bool { return true }), l.{{.Method}}(func({{.Type}}) bool { return false }))
+            {{end}}
+            // Output: true false
+        }
+        `),
+	"Contains": parseExampleTemplate("Contains", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            fmt.Println(l.{{.Method}}(2), l.{{.Method}}(4))
+            // Output: true false
+            {{else}}
+            fmt.Println(l.{{.Method}}(zero))
+            // Output: true
+            {{end}}
+        }
+        `),
+	"Sort": parseExampleTemplate("Sort", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            fmt.Println([]{{.Type}}(l.{{.Method}}()))
+            // Output: [1 2 3]
+            {{else}}
+            fmt.Println(len(l.{{.Method}}()))
+            // Output: 3
+            {{end}}
+        }
+        `),
+	"Sum": parseExampleTemplate("Sum", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            fmt.Println(l.{{.Method}}())
+            // Output: 6
+        }
+        `),
+	"Min": parseExampleTemplate("Min", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            fmt.Println(l.{{.Method}}())
+            // Output: 1 true
+            {{else}}
+            _, ok := l.{{.Method}}()
+            fmt.Println(ok)
+            // Output: true
+            {{end}}
+        }
+        `),
+	"Max": parseExampleTemplate("Max", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            fmt.Println(l.{{.Method}}())
+            // Output: 3 true
+            {{else}}
+            _, ok := l.{{.Method}}()
+            fmt.Println(ok)
+            // Output: true
+            {{end}}
+        }
+        `),
+	"FilterMap":  parseExampleTemplate("FilterMap", filterMapExampleText),
+	"PFilterMap": parseExampleTemplate("PFilterMap", filterMapExampleText),
+	"FilterMapTo": parseExampleTemplate("FilterMapTo", `{{if .TargetName}}
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            var u {{.TargetType}}
+            {{if .Numeric}}
+            fmt.Println(len(l.{{.Method}}(func(v {{.Type}}) ({{.TargetType}}, bool) { return u, v > 1 })))
+            // Output: 2
+            {{else}}
+            fmt.Println(len(l.{{.Method}}(func({{.Type}}) ({{.TargetType}}, bool) { return u, true })))
+            // Output: 3
+            {{end}}
+        }
+        {{end}}`),
+	"MapNotNil": parseExampleTemplate("MapNotNil", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            var u {{.TargetType}}
+            {{if .Numeric}}
+            fmt.Println(len(l.{{.Method}}(func(v {{.Type}}) *{{.TargetType}} {
+                if v > 1 {
+                    return &u
+                }
+                return nil
+            })))
+            // Output: 2
+            {{else}}
+            fmt.Println(len(l.{{.Method}}(func({{.Type}}) *{{.TargetType}} { return &u })))
+            // Output: 3
+            {{end}}
+        }
+        `),
+	"FlatMap": parseExampleTemplate("FlatMap", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            var u {{.TargetType}}
+            fmt.Println(len(l.{{.Method}}(func({{.Type}}) {{.TargetList}} { return {{.TargetList}}{u, u} })))
+            // Output: 6
+        }
+        `),
+	"Fold": parseExampleTemplate("Fold", `{{if .TargetName}}
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            var u {{.TargetType}}
+            n := 0
+            l.{{.Method}}(u, func(acc {{.TargetType}}, _ {{.Type}}) {{.TargetType}} {
+                n++
+                return acc
+            })
+            fmt.Println(n)
+            // Output: 3
+        }
+        {{end}}`),
+	"Zip": parseExampleTemplate("Zip", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            var u {{.TargetType}}
+            fmt.Println(len(l.{{.Method}}({{.TargetList}}{u, u}, func(v {{.Type}}, _ {{.TargetType}}) {{.Type}} { return v })))
+            // Output: 2
+        }
+        `),
+	"GroupBy": parseExampleTemplate("GroupBy", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            var u {{.TargetType}}
+            groups := l.{{.Method}}(func({{.Type}}) {{.TargetType}} { return u })
+            fmt.Println(len(groups), len(groups[u]))
+            // Output: 1 3
+        }
+        `),
+	"CountBy": parseExampleTemplate("CountBy", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            var u {{.TargetType}}
+            fmt.Println(l.{{.Method}}(func({{.Type}}) {{.TargetType}} { return u })[u])
+            // Output: 3
+        }
+        `),
+	"SumBy": parseExampleTemplate("SumBy", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            fmt.Println(l.{{.Method}}(func({{.Type}}) {{.TargetType}} { return 1 }))
+            // Output: 3
+        }
+        `),
+	"MinBy": parseExampleTemplate("MinBy", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if and .Numeric (not .TargetName)}}
+            fmt.Println(l.{{.Method}}(func(v {{.Type}}) {{.Type}} { return -v }))
+            // Output: 3 true
+            {{else}}
+            var u {{.TargetType}}
+            _, ok := l.{{.Method}}(func({{.Type}}) {{.TargetType}} { return u })
+            fmt.Println(ok)
+            // Output: true
+            {{end}}
+        }
+        `),
+	"MaxBy": parseExampleTemplate("MaxBy", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if and .Numeric (not .TargetName)}}
+            fmt.Println(l.{{.Method}}(func(v {{.Type}}) {{.Type}} { return -v }))
+            // Output: 1 true
+            {{else}}
+            var u {{.TargetType}}
+            _, ok := l.{{.Method}}(func({{.Type}}) {{.TargetType}} { return u })
+            fmt.Println(ok)
+            // Output: true
+            {{end}}
+        }
+        `),
+	"By": parseExampleTemplate("By", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            sort.Sort(l.{{.Method}}(func(a, b {{.Type}}) bool { return a > b }))
+            fmt.Println([]{{.Type}}(l))
+            // Output: [3 2 1]
+            {{else}}
+            sort.Stable(l.{{.Method}}(func(a, b {{.Type}}) bool { return false }))
+            fmt.Println(len(l))
+            // Output: 3
+            {{end}}
+        }
+        `),
+	"FilterInPlace": parseExampleTemplate("FilterInPlace", filterExampleText),
+	"CompactNil": parseExampleTemplate("CompactNil", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            fmt.Println(len(l.{{.Method}}()))
+            // Output: 0
+        }
+        `),
+	"Grow": parseExampleTemplate("Grow", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            l = l.{{.Method}}(10)
+            fmt.Println(len(l), cap(l) >= 13)
+            // Output: 3 true
+        }
+        `),
+	"Clip": parseExampleTemplate("Clip", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            l = l[:2]
+            l = l.{{.Method}}()
+            fmt.Println(len(l), cap(l))
+            // Output: 2 2
+        }
+        `),
+	"Heap": parseExampleTemplate("Heap", `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            h := l.{{.Method}}(func(a, b {{.Type}}) bool { return a < b })
+            heap.Init(h)
+            heap.Push(h, {{.Type}}(0))
+            fmt.Println(heap.Pop(h), heap.Pop(h))
+            // Output: 0 1
+            {{else}}
+            h := l.{{.Method}}(func(a, b {{.Type}}) bool { return false })
+            heap.Init(h)
+            heap.Push(h, zero)
+            fmt.Println(h.Len())
+            // Output: 4
+            {{end}}
+        }
+        `),
+	"String": parseExampleTemplate("String", `
+        func Example{{.List}}_String() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            fmt.Println(l.String())
+            {{if le .StringMax 0}}// Output: [3{{.StringSep}}1{{.StringSep}}2]{{end}}
+            {{else}}
+            fmt.Println(len(l.String()) > 0)
+            // Output: true
+            {{end}}
+        }
+        `),
+	"SQL": parseExampleTemplate("SQL", `
+        func Example{{.List}}_Value() {
+            {{template "list" .}}
+            value, err := l.Value()
+            if err != nil {
+                fmt.Println(err)
+                return
+            }
+            {{if and .Numeric (ne .SQLEncoding "json")}}
+            fmt.Println(value)
+            // Output: {3,1,2}
+            {{else}}
+            fmt.Println(value != nil)
+            // Output: true
+            {{end}}
+        }
+        `),
+	"JSON": parseExampleTemplate("JSON", `
+        func Example{{.List}}_{{method "ToJSON"}}() {
+            {{template "list" .}}
+            b, err := l.{{method "ToJSON"}}()
+            if err != nil {
+                fmt.Println(err)
+                return
+            }
+            l2, err := {{funcName "From" .List "JSON"}}(b)
+            fmt.Println(len(l2), err)
+            // Output: 3 <nil>
+        }
+        `),
+	"Builder": parseExampleTemplate("Builder", `
+        func Example{{constructorName (print .List "Builder")}}() {
+            var zero {{.Type}}
+            l := {{constructorName (print .List "Builder")}}(0).Add(zero).AddIf(false, zero).AddAll(zero, zero).Build()
+            fmt.Println(len(l))
+            // Output: 3
+        }
+        `),
+	"SafeList": parseExampleTemplate("SafeList", `
+        func Example{{wrapperName .List "SafeList"}}() {
+            var zero {{.Type}}
+            var s {{wrapperName .List "SafeList"}}
+            var wg sync.WaitGroup
+            for i := 0; i < 3; i++ {
+                wg.Add(1)
+                go func() {
+                    defer wg.Done()
+                    s.Append(zero)
+                }()
+            }
+            wg.Wait()
+            fmt.Println(len(s.Snapshot()))
+            // Output: 3
+        }
+        `),
+	"Vector": parseExampleTemplate("Vector", `
+        func Example{{constructorName (wrapperName .List "Vector")}}() {
+            var zero {{.Type}}
+            v := {{constructorName (wrapperName .List "Vector")}}(zero, zero)
+            v2 := v.Append(zero)
+            fmt.Println(v.Len(), v2.Len())
+            // Output: 2 3
+        }
+        `),
+	"Pipeline": parseExampleTemplate("Pipeline", `
+        func Example{{constructorName (wrapperName .List "Pipeline")}}() {
+            {{template "list" .}}
+            {{- if .Numeric}}
+            l2, err := {{constructorName (wrapperName .List "Pipeline")}}(l).Map(func(v {{.Type}}) {{.Type}} { return v * 2 }).Filter(func(v {{.Type}}) bool { return v > 2 }).Workers(2).Run()
+            fmt.Println([]{{.Type}}(l2), err)
+            // Output: [6 4] <nil>
+            {{- else}}
+            l2, err := {{constructorName (wrapperName .List "Pipeline")}}(l).Filter(func({{.Type}}) bool { return true }).Workers(2).Run()
+            fmt.Println(len(l2), err)
+            // Output: 3 <nil>
+            {{- end}}
+        }
+        `),
+}
+
+// mapExampleText - the example template of Map, PMap and MapInPlace
+const mapExampleText = `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if and .Numeric (not .TargetName)}}
+            fmt.Println([]{{.Type}}(l.{{.Method}}(func(v {{.Type}}) {{.Type}} { return v * 2 })))
+            // Output: [6 2 4]
+            {{else}}
+            var u {{.TargetType}}
+            fmt.Println(len(l.{{.Method}}(func({{.Type}}) {{.TargetType}} { return u })))
+            // Output: 3
+            {{end}}
+        }
+        `
+
+// takeExampleText - the example template of Take and Truncate
+const takeExampleText = `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            fmt.Println([]{{.Type}}(l.{{.Method}}(2)))
+            // Output: [3 1]
+            {{else}}
+            fmt.Println(len(l.{{.Method}}(2)))
+            // Output: 2
+            {{end}}
+        }
+        `
+
+// filterExampleText - the example template of Filter and FilterInPlace
+const filterExampleText = `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            fmt.Println([]{{.Type}}(l.{{.Method}}(func(v {{.Type}}) bool { return v > 1 })))
+            // Output: [3 2]
+            {{else}}
+            fmt.Println(len(l.{{.Method}}(func({{.Type}}) bool { return true })))
+            // Output: 3
+            {{end}}
+        }
+        `
+
+// reduceExampleText - the example template of Reduce and ReduceRight, which shows the order in which the members are
+// reduced with values fitting in any numeric type
+const reduceExampleText = `
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            {{if .Numeric}}
+            {{if eq .Method "Reduce"}}
+            fmt.Println(l.{{.Method}}(0, func(acc, v {{.Type}}) {{.Type}} { return acc*2 + v }))
+            // Output: 16
+            {{else}}
+            fmt.Println(l.{{.Method}}(0, func(v, acc {{.Type}}) {{.Type}} { return acc*2 + v }))
+            // Output: 13
+            {{end}}
+            {{else}}
+            n := 0
+            l.{{.Method}}(zero, func(acc, _ {{.Type}}) {{.Type}} {
+                n++
+                return acc
+            })
+            fmt.Println(n)
+            // Output: 3
+            {{end}}
+        }
+        `
+
+// filterMapExampleText - the example template of FilterMap and PFilterMap
+const filterMapExampleText = `{{if .TargetName}}
+        func Example{{.List}}_{{.Method}}() {
+            {{template "list" .}}
+            var u {{.TargetType}}
+            {{if .Numeric}}
+            fmt.Println(len(l.{{.Method}}(func({{.Type}}) {{.TargetType}} { return u }, func(v {{.Type}}) bool { return v > 1 })))
+            // Output: 2
+            {{else}}
+            fmt.Println(len(l.{{.Method}}(func({{.Type}}) {{.TargetType}} { return u })))
+            // Output: 3
+            {{end}}
+        }
+        {{end}}`
@@ -0,0 +1,3011 @@
+package fungen
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/build/constraint"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:generate $GOPATH/bin/fungen -types "Generator" -methods Filter,Each
+
+// Generator - one generator (function and information about generate)
+type Generator struct {
+	// Name - the name of the method, which selects it in -methods
+	Name string
+	// Method - get the source of the method on the list listName of typeName and, for the cross-type methods, the
+	// target type targetType named targetTypeName, which is empty when the target is the type itself
+	Method func(listName, typeName, targetType, targetTypeName string) string
+	// NeedSync - whether the method uses the sync package
+	NeedSync bool
+	// NeedMapToMap - whether the method is a cross-type method, generated for each target type
+	NeedMapToMap bool
+	// OptIn - whether the method is only generated when it is given in -methods
+	OptIn bool
+	// Imports - the import paths of the packages the method uses
+	Imports []string
+	// NeedListType - whether the method needs the list type, and so cannot be generated with -funcs
+	NeedListType bool
+	// Supports - whether the method can be generated for typeName and, for the cross-type methods, targetType. The
+	// method is generated for all the types if it is nil
+	Supports func(typeName, targetType string) bool
+	// Requires - the trait the method requires, described by list-methods -json
+	Requires string
+}
+
+// flagSet - the flags of fungen, which are the options of the generation
+var flagSet = flag.NewFlagSet("fungen", flag.ExitOnError)
+
+var (
+	packageName = flagSet.String("package", "main", "(Optional) Name of the package. By default it is $GOPACKAGE when run by go generate, or the package of the files in the directory of the generated file, or 'main'.")
+	types       = flagSet.String("types", "", "Comma-separated list of type names, eg. 'int,string,CustomType'. The values can themselves be colon (:) separated to specify the names of entities in the generated, eg: int:I,string:Str,CustomType:CT. A third colon separated value gives the complete name of the list type, eg: int::Ints,string:Str:Strings. A fourth one gives the methods of the type, overriding -methods, in which case the types are separated by semicolons, eg: 'User:U::Map,Filter;int:I::all'.")
+	methods     = flagSet.String("methods", "", "Comma-separated list of methods to generate, eg 'Map,Filter'. By default generate all methods except the opt-in ones (Heap, String, SQL, JSON, Builder, SafeList, Vector, Pipeline).")
+	excluded    = flagSet.String("exclude-methods", "", "(Optional) Comma-separated list of methods not to generate, eg. 'PMap,PFilter', which are removed from the -methods or from all the methods by default.")
+	outputName  = flagSet.String("filename", "fungen_auto.go", "(Optional) Filename for generated package, or '-' to write it to the standard output.")
+	stringSep   = flagSet.String("string-sep", ", ", "(Optional) Separator between the members in the representation returned by the String method.")
+	stringMax   = flagSet.Int("string-max", 0, "(Optional) Maximum number of members shown in the representation returned by the String method, the rest is summarized. By default all members are shown.")
+	sqlEncoding = flagSet.String("sql-encoding", "json", "(Optional) Column encoding used by the SQL method, either 'json' or 'postgres' (array literals).")
+	jsonEmpty   = flagSet.Bool("json-nil-empty", false, "(Optional) Whether the JSON method also generates a MarshalJSON method which encodes nil lists as [] instead of null.")
+	pairs       = flagSet.String("pairs", "", "(Optional) Semicolon-separated list of comma-separated type pairs, eg. 'int,string;string,CustomType'. A pair struct and a list type for it are generated for each pair. Types listed in -types use their names from there.")
+	funcs       = flagSet.Bool("funcs", false, "(Optional) Whether to generate package-level functions taking plain slices, eg. 'MapIntList(l []int, f func(int) int) []int', instead of list types with methods.")
+	interfaces  = flagSet.Bool("interfaces", false, "(Optional) Whether to also generate an interface describing the methods of each list type, eg. 'IntLister' for 'intList'.")
+	importPaths = flagSet.String("import", "", "(Optional) Comma-separated list of name=path import paths for the packages of qualified types, eg. 'uuid=github.com/google/uuid'. By default they are resolved from the imports of the package and its dependencies.")
+	ptrReceiver = flagSet.Bool("pointer-receiver", false, "(Optional) Whether to generate the methods on the list types with pointer receivers, eg. 'func (l *intList) Map(...)'.")
+	unexported  = flagSet.Bool("unexported", false, "(Optional) Whether to generate unexported method names, eg. 'filter' instead of 'Filter'. Methods implementing standard interfaces, like String, stay exported.")
+	listSuffix  = flagSet.String("suffix", "List", "(Optional) Suffix appended to the names of the types to name the generated list types. It can be empty if all the types are given names.")
+	receiver    = flagSet.String("receiver", "l", "(Optional) Name of the receiver of the generated methods, or 'auto' for the lowercased initials of the list type name, eg. 'il' for 'intList'.")
+	traits      = flagSet.String("traits", "", "(Optional) Comma-separated list of types with their plus (+) separated traits, eg. 'Celsius+numeric,UserID+comparable'. The traits, comparable, ordered and numeric, enable the methods which need them. They are detected for the predeclared types and the types found in the package, and can also be given in -types, eg. 'int:I+numeric'.")
+	noCrossMaps = flagSet.Bool("no-cross-maps", false, "(Optional) Whether to generate Map and the other cross-type methods only from each type to itself, eg. 'Map' but not 'MapString' on 'intList'.")
+	mapTargets  = flagSet.String("map-targets", "", "(Optional) Comma-separated list of source>target type pairs restricting the cross-type methods which are generated, eg. 'User>string,User>int'. The types can be given by their names from -types. By default the cross-type methods are generated for all pairs of types.")
+	fullSlice   = flagSet.Bool("full-slice", false, "(Optional) Whether Take, TakeWhile, Drop and DropWhile slice the original list with full slice expressions, eg. 'l[:n:n]', so that appending to their results cannot modify the original list.")
+	copyResults = flagSet.Bool("copy", false, "(Optional) Whether Take, TakeWhile, Drop and DropWhile return copies instead of sub-slices sharing the backing array of the original list.")
+	subpackage  = flagSet.String("subpackage", "", "(Optional) Name of a subpackage to generate the files in, eg. 'lists', in a directory of that name created in the directory of the generated files if needed. The generated types are declared in the subpackage, which imports the package of the member types, so that they stay out of its documentation.")
+	outputDir   = flagSet.String("outdir", "", "(Optional) Directory of the generated files. By default it is the directory of -filename.")
+	split       = flagSet.Bool("split", false, "(Optional) Whether to generate one file per type, eg. 'fungen_user.go' for 'User', instead of a single file named by -filename.")
+	discover    = flagSet.Bool("discover", false, "(Optional) Whether to generate the methods on the named slice types declared in the package of the generated file, eg. 'type Users []User', without declaring them again.")
+	configFile  = flagSet.String("config", "", "(Optional) Path of a JSON configuration file declaring the package, types, methods and options. By default '"+defaultConfigFile+"' is read if it exists. The flags given on the command line override it.")
+	check       = flagSet.Bool("check", false, "(Optional) Whether to only check that the generated files are up to date, exiting with status 1 if they are not, instead of writing them.")
+	showDiff    = flagSet.Bool("diff", false, "(Optional) Whether to print the unified diff between the generated files on disk and the files which would be generated, instead of writing them.")
+	force       = flagSet.Bool("force", false, "(Optional) Whether to write the generated files over existing files which were not generated by fungen. By default fungen refuses to, so that a mistyped -filename does not destroy a hand-written file.")
+	forceWrite  = flagSet.Bool("force-write", false, "(Optional) Whether to write the generated files even if they are already up to date. By default they are left untouched so that their modification times do not change.")
+	genTests    = flagSet.Bool("gen-tests", false, "(Optional) Whether to also generate a test file for each generated file, eg. 'fungen_auto_test.go', with table-driven tests of the generated methods.")
+	genExamples = flagSet.Bool("gen-examples", false, "(Optional) Whether to also generate an examples file for each generated file, eg. 'fungen_auto_example_test.go', with an Example function demonstrating each generated method of the exported list types in godoc.")
+	genFuzz     = flagSet.Bool("gen-fuzz", false, "(Optional) Whether to also generate a fuzz test file for each generated file, eg. 'fungen_auto_fuzz_test.go', with a fuzz target per list type checking the invariants of the generated methods, eg. that Take(n) and Drop(n) put together give the list back.")
+	genProps    = flagSet.Bool("gen-props", false, "(Optional) Whether to also generate a property test file for each generated file, eg. 'fungen_auto_prop_test.go', with a testing/quick test per list type of ordered members checking the laws of the generated methods, eg. that Reduce and ReduceRight agree for an associative function.")
+	nolint      = flagSet.String("nolint", "", "(Optional) Comma-separated list of linters, eg. 'unused,gocyclo', or 'all', for the //nolint directive added to each generated declaration, so that the strict linters do not report the generated code.")
+	coverIgnore = flagSet.String("coverage-ignore", "", "(Optional) Comment added before the package clause of the generated files, except the test files, to exclude them from the coverage by the tools which support it, eg. '//coverage:ignore'.")
+	docsFile    = flagSet.String("docs", "", "(Optional) Path of a markdown file to write a reference of the generated types, methods and functions to, with their signatures and doc comments, eg. 'API.md', so that the generated API can be read without the sources.")
+	genDoc      = flagSet.Bool("gen-doc", false, "(Optional) Whether to also generate a file for the generated file, eg. 'fungen_auto_doc.go', with a package comment summarizing the generated types and their methods, so that the documentation of the package gives an overview of the generated API.")
+	gofumpt     = flagSet.Bool("gofumpt", false, "(Optional) Whether to format the generated files with the rules of gofumpt which apply to them, so that gofumpt leaves them as they are.")
+	showPlan    = flagSet.Bool("plan", false, "(Optional) Whether to print, without generating any code, a JSON description of what would be generated: the files with their list types, the types of their members and their methods.")
+	verbose     = flagSet.Bool("v", false, "(Optional) Whether to print the progress of the generation, by type and method, along with its timings.")
+	quiet       = flagSet.Bool("q", false, "(Optional) Whether to only print the errors, without the warnings and the notices.")
+	vet         = flagSet.Bool("vet", false, "(Optional) Whether to run go vet on the package with the generated files before writing them, and not write them if go vet reports findings in the generated code.")
+	buildTags   = flagSet.String("tags", "", "(Optional) Build constraint expression written as '//go:build' and '// +build' lines at the top of the generated files, eg. '!js && !wasm'.")
+	appendMode  = flagSet.Bool("append", false, "(Optional) Whether to add the generated code to the existing generated file as a section marked with the command line, so that several fungen commands can generate the same file. Running a command again replaces its section.")
+	mergeMode   = flagSet.Bool("merge", false, "(Optional) Whether to keep the types of the existing generated file which are not generated again, along with their methods, and only replace the types which are generated.")
+	templates   = flagSet.String("templates", "", "(Optional) Directory of text/template files overriding the generation of the methods they are named after, eg. 'Map.tmpl'. The other methods are generated as usual.")
+	headerFile  = flagSet.String("header-file", "", "(Optional) Path of a file, eg. a license header, whose comments are written at the top of the generated files, before the package clause.")
+	watch       = flagSet.Bool("watch", false, "(Optional) Whether to keep running and generate the files again whenever the Go files of the package or the configuration file change.")
+	onConflict  = flagSet.String("on-conflict", "error", "(Optional) What to do with the generated identifiers which are already declared in the target package, eg. a list type declared by hand: 'error' reports them, 'suffix' generates them with a number suffix, eg. 'intList2', with a warning. The methods already declared on the types are always reported.")
+	lenient     = flagSet.Bool("lenient", false, "(Optional) Whether to skip the unknown methods, the types which cannot be used and the other problems which stop the generation by default, and to print a summary of them along with the methods given in -methods which are not generated for some types, eg. Sum for a struct type.")
+	style       = flagSet.String("style", "fungen", "(Optional) Naming style of the generated methods, either 'fungen', 'lo', which names the methods with an equivalent in samber/lo after it, eg. 'ForEach' and 'SomeBy' instead of 'Each' and 'Any', or 'slices', which names them after the standard slices package, eg. 'ContainsFunc' instead of 'Any'.")
+	nameCase    = flagSet.String("name-case", "initialisms", "(Optional) Casing of the type names in the generated names, eg. 'URL' in 'MapURL' for the type 'url', either 'initialisms', which upper cases the common initialisms like 'ID' and 'URL' as a whole, or 'title', which only upper cases the first letter, eg. 'MapUrl'.")
+	goVersion   = flagSet.String("go", "", "(Optional) Go version of the toolchain building the generated code, eg. '1.21', which selects its idioms: the any alias from 1.18, the slices package from 1.21 and the iter.Seq adapters, Values and Backward, from 1.23. By default the code builds with all the toolchains.")
+	cpuProfile  = flagSet.String("cpuprofile", "", "(Optional) Path of a file to write the CPU profile of fungen to, to be read with 'go tool pprof', eg. to report why the generation of many types is slow.")
+	memProfile  = flagSet.String("memprofile", "", "(Optional) Path of a file to write the memory profile of fungen to, once the files are generated, to be read with 'go tool pprof'.")
+	showVersion = flagSet.Bool("version", false, "(Optional) Whether to print the version of fungen and the methods it supports, instead of generating.")
+	testrun     = flagSet.Bool("test", false, "whether to display the generated code instead of writing out to a file.")
+	listNames   = map[string]string{}
+	pairNames   = map[[2]string]string{}
+	typeMethods = map[string]string{}
+	existLists  = map[string]bool{}
+	commandLine = ""
+	generators  = GeneratorList{
+		{
+			Name:         "Map",
+			Method:       getMapFunction,
+			NeedSync:     false,
+			NeedMapToMap: true,
+		},
+		{
+			Name:         "PMap",
+			Method:       getPMapFunction,
+			NeedSync:     true,
+			NeedMapToMap: true,
+		},
+		{
+			Name:     "Filter",
+			Method:   getFilterFunction,
+			NeedSync: false,
+		},
+		{
+			Name:     "PFilter",
+			Method:   getPFilterFunction,
+			NeedSync: true,
+		},
+		{
+			Name:   "Reduce",
+			Method: getReduceFunction,
+		},
+		{
+			Name:   "ReduceRight",
+			Method: getReduceRightFunction,
+		},
+		{
+			Name:   "Take",
+			Method: getTakeFunction,
+		},
+		{
+			Name:   "TakeWhile",
+			Method: getTakeWhileFunction,
+		},
+		{
+			Name:   "Drop",
+			Method: getDropFunction,
+		},
+		{
+			Name:   "DropWhile",
+			Method: getDropWhileFunction,
+		},
+		{
+			Name:   "Each",
+			Method: getEachFunction,
+		},
+		{
+			Name:   "EachI",
+			Method: getEachIFunction,
+		},
+		{
+			Name:   "All",
+			Method: getAllFunction,
+		},
+		{
+			Name:   "Any",
+			Method: getAnyFunction,
+		},
+		{
+			Name:     "Contains",
+			Method:   getContainsFunction,
+			Supports: isComparableType,
+			Requires: "comparable",
+		},
+		{
+			Name:     "Sort",
+			Method:   getSortFunction,
+			Imports:  []string{"sort"},
+			Supports: isOrderedType,
+			Requires: "ordered",
+		},
+		{
+			Name:     "Sum",
+			Method:   getSumFunction,
+			Supports: isNumericType,
+			Requires: "numeric",
+		},
+		{
+			Name:     "Min",
+			Method:   getMinFunction,
+			Supports: isOrderedType,
+			Requires: "ordered",
+		},
+		{
+			Name:     "Max",
+			Method:   getMaxFunction,
+			Supports: isOrderedType,
+			Requires: "ordered",
+		},
+		{
+			Name:         "FilterMap",
+			Method:       getFilterMapFunction,
+			NeedSync:     false,
+			NeedMapToMap: true,
+		},
+		{
+			Name:         "PFilterMap",
+			Method:       getPFilterMapFunction,
+			NeedSync:     true,
+			NeedMapToMap: true,
+		},
+		{
+			Name:         "FilterMapTo",
+			Method:       getFilterMapToFunction,
+			NeedMapToMap: true,
+		},
+		{
+			Name:         "MapNotNil",
+			Method:       getMapNotNilFunction,
+			NeedMapToMap: true,
+		},
+		{
+			Name:         "FlatMap",
+			Method:       getFlatMapFunction,
+			NeedMapToMap: true,
+		},
+		{
+			Name:         "Fold",
+			Method:       getFoldFunction,
+			NeedMapToMap: true,
+		},
+		{
+			Name:         "Zip",
+			Method:       getZipFunction,
+			NeedMapToMap: true,
+		},
+		{
+			Name:         "ZipPairs",
+			Method:       getZipPairsFunction,
+			NeedMapToMap: true,
+			Supports:     hasPair,
+		},
+		{
+			Name:     "Enumerate",
+			Method:   getEnumerateFunction,
+			Supports: func(typeName, _ string) bool { return hasPair("int", typeName) },
+		},
+		{
+			Name:     "RunLengthEncode",
+			Method:   getRunLengthEncodeFunction,
+			Supports: func(typeName, _ string) bool { return isComparableType(typeName, "") && hasPair(typeName, "int") },
+			Requires: "comparable",
+		},
+		{
+			Name:         "GroupBy",
+			Method:       getGroupByFunction,
+			NeedMapToMap: true,
+			Supports:     isComparableType,
+			Requires:     "comparable",
+		},
+		{
+			Name:         "CountBy",
+			Method:       getCountByFunction,
+			NeedMapToMap: true,
+			Supports:     isComparableType,
+			Requires:     "comparable",
+		},
+		{
+			Name:         "SumBy",
+			Method:       getSumByFunction,
+			NeedMapToMap: true,
+			Supports:     isNumericType,
+			Requires:     "numeric",
+		},
+		{
+			Name:         "MinBy",
+			Method:       getMinByFunction,
+			NeedMapToMap: true,
+			Supports:     isNumericType,
+			Requires:     "numeric",
+		},
+		{
+			Name:         "MaxBy",
+			Method:       getMaxByFunction,
+			NeedMapToMap: true,
+			Supports:     isNumericType,
+			Requires:     "numeric",
+		},
+		{
+			Name:         "By",
+			Method:       getByFunction,
+			NeedListType: true,
+		},
+		{
+			Name:   "MapInPlace",
+			Method: getMapInPlaceFunction,
+		},
+		{
+			Name:   "FilterInPlace",
+			Method: getFilterInPlaceFunction,
+		},
+		{
+			Name:     "CompactNil",
+			Method:   getCompactNilFunction,
+			Supports: isNilableType,
+			Requires: "nilable",
+		},
+		{
+			Name:   "Grow",
+			Method: getGrowFunction,
+		},
+		{
+			Name:   "Clip",
+			Method: getClipFunction,
+		},
+		{
+			Name:   "Truncate",
+			Method: getTruncateFunction,
+		},
+		{
+			Name:     "Values",
+			Method:   getValuesFunction,
+			Imports:  []string{"iter", "slices"},
+			Supports: supportsIter,
+			Requires: "go1.23",
+		},
+		{
+			Name:     "Backward",
+			Method:   getBackwardFunction,
+			Imports:  []string{"iter", "slices"},
+			Supports: supportsIter,
+			Requires: "go1.23",
+		},
+		{
+			Name:         "Heap",
+			Method:       getHeapFunction,
+			OptIn:        true,
+			NeedListType: true,
+		},
+		{
+			Name:         "String",
+			Method:       getStringFunction,
+			OptIn:        true,
+			Imports:      []string{"bytes", "fmt"},
+			Supports:     isEncodableType,
+			Requires:     "encodable",
+			NeedListType: true,
+		},
+		{
+			Name:         "SQL",
+			Method:       getSQLFunction,
+			OptIn:        true,
+			Imports:      []string{"database/sql/driver", "encoding/json", "fmt"},
+			NeedListType: true,
+			Supports:     isEncodableType,
+			Requires:     "encodable",
+		},
+		{
+			Name:         "JSON",
+			Method:       getJSONFunction,
+			OptIn:        true,
+			Imports:      []string{"encoding/json"},
+			NeedListType: true,
+			Supports:     isEncodableType,
+			Requires:     "encodable",
+		},
+		{
+			Name:         "Builder",
+			Method:       getBuilderFunction,
+			OptIn:        true,
+			NeedListType: true,
+		},
+		{
+			Name:         "SafeList",
+			Method:       getSafeListFunction,
+			NeedSync:     true,
+			OptIn:        true,
+			NeedListType: true,
+		},
+		{
+			Name:         "Vector",
+			Method:       getVectorFunction,
+			OptIn:        true,
+			NeedListType: true,
+		},
+		{
+			Name:         "Pipeline",
+			Method:       getPipelineFunction,
+			NeedSync:     true,
+			OptIn:        true,
+			Imports:      []string{"context"},
+			NeedListType: true,
+		},
+	}
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of fungen:\n")
+	fmt.Fprintf(os.Stderr, "\tgen -package packageName -types Types\n")
+	fmt.Fprintf(os.Stderr, "\tgen command [flags]\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	fmt.Fprintf(os.Stderr, "\tgenerate      generate the files, which is the default when no command is given\n")
+	fmt.Fprintf(os.Stderr, "\tcheck         check that the generated files are up to date, like -check\n")
+	fmt.Fprintf(os.Stderr, "\tlist-methods  list the methods which can be generated\n")
+	fmt.Fprintf(os.Stderr, "\tversion       print the version of fungen, like -version\n")
+	fmt.Fprintf(os.Stderr, "\tinit          propose a configuration file and a go:generate directive for the package, and write them once confirmed\n")
+	fmt.Fprintf(os.Stderr, "\tmigrate       rewrite the calls to the generated methods into calls to generic functions, written along with the package\n")
+	fmt.Fprintf(os.Stderr, "Example:\n")
+	fmt.Fprintf(os.Stderr, "'fungen -package mypackage -types string,int,customType,AnotherType' will create types 'stringList []string, intList []int, customTypeList []customType, AnotherTypeList []AnotherType' with the Map, Filter, Reduce, ReduceRight, Take, TakeWhile, Drop, DropWhile, Each, EachI methods on them. Additionally, methods named MapType1Type2 will be generated on these types for the remaining types. The package of the generated file will be 'mypackage' \n\n")
+	fmt.Fprintf(os.Stderr, "'fungen -types string,int:I,customType:CT,AnotherType:At' will create types 'stringList []string, IList []int, CTList []customType, AtList []AnotherType'. The 'stringList' type will have the Map, Filter, Reduce, ReduceRight, Take, TakeWhile, Drop, DropWhile, Each, EachI methods on it. Additionally, it will also have MapI, MapCt and MapAt methods. The package of the generated file will be 'main' \n\n")
+	fmt.Fprintf(os.Stderr, "'fungen -methods Map,Filter -types int' will create types 'intList []int' with the Map, Filter methods on them.\n\n")
+
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flagSet.PrintDefaults()
+}
+
+// Main - run the fungen command line with the arguments args, without the program name, which are either flags or a
+// command followed by its flags, and exit with status 1 if it fails
+func Main(args []string) {
+	exitOnError(runMain(args))
+}
+
+// runMain - run the fungen command line with the arguments args and return the error which stopped it, if any
+func runMain(args []string) (err error) {
+	defer recoverFatal(&err)
+	flagSet.Usage = usage
+	if len(args) > 0 {
+		if command, ok := commands[args[0]]; ok {
+			command(args[1:])
+			return nil
+		}
+	}
+	parseFlags(args)
+	run()
+	return nil
+}
+
+// run - generate the files according to the flags, which are already parsed
+func run() {
+	commandLine = getCommandLine(flagSet)
+	if *showVersion {
+		fmt.Print(getVersionInfo())
+		return
+	}
+	if *watch {
+		watchFiles()
+		return
+	}
+	defer startProfiling()()
+
+	if packages := loadConfig(*configFile); len(packages) > 0 {
+		runPackages(packages)
+		return
+	}
+	generateAndWrite()
+}
+
+// generateAndWrite - generate the files according to the flags and the configuration file, which is already loaded,
+// and write them, or print what -plan, -check or -diff ask for instead
+func generateAndWrite() {
+	defer warnSkippedProblems()
+	if *showPlan {
+		g, err := prepareGeneration()
+		if err == errNoTypes {
+			flagSet.Usage()
+			exitWith(2)
+		} else if err != nil {
+			fatalf("%s", err)
+		}
+		fmt.Println(getPlanJSON(g))
+		return
+	}
+
+	start := time.Now()
+	files, err := generateFiles()
+	if err == errNoTypes {
+		flagSet.Usage()
+		exitWith(2)
+	} else if err != nil {
+		fatalf("%s", err)
+	}
+
+	fileNames := []string{}
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	reference := ""
+	if *docsFile != "" {
+		reference = generateMarkdownReference(files)
+	}
+
+	if *check {
+		stale := []string{}
+		for _, fileName := range fileNames {
+			if staleness := getStaleness(getOutputPath(fileName), files[fileName]); staleness != "" {
+				stale = append(stale, staleness)
+			}
+		}
+		if *docsFile != "" {
+			if staleness := getStaleness(*docsFile, reference); staleness != "" {
+				stale = append(stale, staleness)
+			}
+		}
+		if len(stale) > 0 {
+			fmt.Fprintf(os.Stderr, "fungen: the generated files are out of date, run fungen again:\n\t%s\n", strings.Join(stale, "\n\t"))
+			exitWith(1)
+		}
+		return
+	}
+
+	if *showDiff {
+		for _, fileName := range fileNames {
+			path := getOutputPath(fileName)
+			content, err := ioutil.ReadFile(path)
+			if err != nil && !os.IsNotExist(err) {
+				fatalf("reading the generated file '%s' for -diff: %s", path, err)
+			}
+			fmt.Print(getUnifiedDiff("a/"+path, "b/"+path, string(content), files[fileName]))
+		}
+		if *docsFile != "" {
+			content, err := ioutil.ReadFile(*docsFile)
+			if err != nil && !os.IsNotExist(err) {
+				fatalf("reading the reference '%s' for -diff: %s", *docsFile, err)
+			}
+			fmt.Print(getUnifiedDiff("a/"+*docsFile, "b/"+*docsFile, string(content), reference))
+		}
+		return
+	}
+
+	verbosef("generated %d files in %s", len(files), time.Since(start))
+
+	if !*force && !*testrun {
+		for _, fileName := range fileNames {
+			if path := getOutputPath(fileName); !isOverwritable(path) {
+				fatalf("'%s' was not generated by fungen, nothing was written: check -filename or set -force to write over it", path)
+			}
+		}
+		if *docsFile != "" && !isOverwritable(*docsFile) {
+			fatalf("'%s' was not generated by fungen, nothing was written: check -docs or set -force to write over it", *docsFile)
+		}
+	}
+
+	start = time.Now()
+	if err := checkGeneratedFiles(files); err != nil {
+		fatalf("the generated code does not type-check, nothing was written: %s", err)
+	}
+	verbosef("type-checked the generated code in %s", time.Since(start))
+	if *vet {
+		start = time.Now()
+		findings, err := vetGeneratedFiles(files)
+		if err != nil {
+			fatalf("running go vet: %s", err)
+		}
+		if len(findings) > 0 {
+			fatalf("go vet reported findings in the generated code, nothing was written:\n\t%s", strings.Join(findings, "\n\t"))
+		}
+		verbosef("ran go vet on the generated code in %s", time.Since(start))
+	}
+	for _, fileName := range fileNames {
+		if err := writeOutput(fileName, files[fileName]); err != nil {
+			fatalf("%s", err)
+		}
+	}
+	if *docsFile != "" {
+		if err := writeReference(*docsFile, reference); err != nil {
+			fatalf("%s", err)
+		}
+	}
+}
+
+// errNoTypes - the error of generateFiles when there are no types to generate
+var errNoTypes = errors.New("no types or pairs to generate")
+
+// generation - the lists to generate according to the flags: the types mapped to their names, the methods of each
+// type, the types used in the generated files, including the types of the pairs, and the list types mapped to the
+// types of their members
+type generation struct {
+	typeMap         map[string]string
+	typeMethodsMaps map[string]map[string]bool
+	typeNames       []string
+	lists           map[string]string
+}
+
+// fileContents - the types and the pairs whose lists are generated in the file fileName
+type fileContents struct {
+	fileName string
+	typeKeys []string
+	pairList [][2]string
+}
+
+// generateFiles - generate the source of the files according to the flags, by file name
+func generateFiles() (files map[string]string, err error) {
+	defer recoverFatal(&err)
+	g, err := prepareGeneration()
+	if err != nil {
+		return nil, err
+	}
+
+	files = map[string]string{}
+	for _, contents := range getFileContents(g.typeMap) {
+		files[contents.fileName] = generateSource(contents.typeKeys, contents.pairList, g.typeMap, g.typeMethodsMaps, g.typeNames, g.lists)
+		addTestFiles(files, contents.fileName, contents.typeKeys, contents.pairList, g.typeMap, g.typeMethodsMaps, g.typeNames)
+	}
+	resolveConflicts(files)
+	if *subpackage != "" {
+		qualifyParentTypes(files)
+	}
+	if *nolint != "" || *coverIgnore != "" {
+		for fileName, src := range files {
+			files[fileName] = getLintSource(fileName, src)
+		}
+	}
+
+	if *appendMode {
+		for fileName, src := range files {
+			files[fileName] = getAppendedSource(getOutputPath(fileName), src)
+		}
+	} else if *mergeMode {
+		for fileName, src := range files {
+			files[fileName] = getMergedSource(getOutputPath(fileName), src)
+		}
+	}
+	for fileName, src := range files {
+		files[fileName] = getKeptSource(getOutputPath(fileName), src)
+	}
+	if *genDoc {
+		files[getDocFileName(*outputName)] = generateDocSource(files)
+	}
+	if *gofumpt {
+		for fileName, src := range files {
+			files[fileName] = getGofumptSource(src)
+		}
+	}
+
+	return files, nil
+}
+
+// getFileContents - get the generated files with their types and pairs from typeMap and -pairs: one file per type and
+// per pair with -split, or the -filename file otherwise
+func getFileContents(typeMap map[string]string) []fileContents {
+	if !*split {
+		return []fileContents{{*outputName, getSortedKeys(typeMap), getPairs(*pairs)}}
+	}
+	contents := []fileContents{}
+	for _, k := range getSortedKeys(typeMap) {
+		contents = append(contents, fileContents{getSplitFileName(getTypeName(k, typeMap)), []string{k}, nil})
+	}
+	for _, pair := range getPairs(*pairs) {
+		contents = append(contents, fileContents{getSplitFileName(getPairName(pair[0], pair[1], typeMap)), nil, [][2]string{pair}})
+	}
+	return contents
+}
+
+// prepareGeneration - read the types to generate from the flags, the directives or the discovered lists and validate
+// them along with the flags
+func prepareGeneration() (g *generation, err error) {
+	defer recoverFatal(&err)
+	if *subpackage != "" {
+		prepareSubpackage()
+	} else if !isFlagSet("package") {
+		*packageName = getDefaultPackageName()
+	}
+	if *discover {
+		discovered := discoverLists(getOutputDir())
+		if *types != "" {
+			discovered = append([]string{*types}, discovered...)
+		}
+		*types = strings.Join(discovered, ",")
+	} else if *types == "" && *pairs == "" {
+		*types = loadDirectives(getOutputDir())
+	}
+
+	if len(*types) == 0 && len(*pairs) == 0 {
+		return nil, errNoTypes
+	}
+
+	if *funcs && *interfaces {
+		fatalf("-funcs and -interfaces cannot be used together")
+	}
+
+	if *funcs && *ptrReceiver {
+		fatalf("-funcs and -pointer-receiver cannot be used together")
+	}
+
+	if *verbose && *quiet {
+		fatalf("-v and -q cannot be used together")
+	}
+
+	if !conflictStrategies[*onConflict] {
+		fatalf("-on-conflict must be 'error' or 'suffix', not '%s'", *onConflict)
+	}
+
+	if methodStyles[*style] == nil {
+		fatalf("-style must be 'fungen', 'lo' or 'slices', not '%s'", *style)
+	}
+
+	if !nameCasings[*nameCase] {
+		fatalf("-name-case must be 'initialisms' or 'title', not '%s'", *nameCase)
+	}
+
+	if _, err := getGoMinor(*goVersion); err != nil {
+		fatalf("-go: %s", err)
+	}
+
+	if *appendMode && *mergeMode {
+		fatalf("-append and -merge cannot be used together")
+	}
+
+	if (*genTests || *genExamples || *genFuzz || *genProps) && (*funcs || *appendMode || *mergeMode || *outputName == "-") {
+		fatalf("-gen-tests, -gen-examples, -gen-fuzz and -gen-props cannot be used with -funcs, -append, -merge or the standard output")
+	}
+
+	if *genDoc && *outputName == "-" {
+		fatalf("-gen-doc cannot be used with the standard output")
+	}
+
+	if *split && *outputName == "-" {
+		fatalf("-split cannot be used with the standard output, since it writes one file per type")
+	}
+
+	validateLintFlags()
+
+	methodsMap := getMethodsMap(*methods)
+	if *templates != "" {
+		loadTemplates(*templates)
+	}
+
+	typeMap := getTypeMap(*types)
+	addTraitsOption(*traits, typeMap)
+	for k := range typeMap {
+		if err := validateType("-types", k); err != nil {
+			skipOrFatalf("%s", err)
+			delete(typeMap, k)
+		}
+	}
+	validPairs := []string{}
+	for i, pair := range getPairs(*pairs) {
+		valid := true
+		for _, t := range pair {
+			if err := validateType("-pairs", t); err != nil {
+				skipOrFatalf("%s", err)
+				valid = false
+			}
+		}
+		if valid {
+			validPairs = append(validPairs, splitTopLevel(*pairs, ';')[i])
+		}
+	}
+	*pairs = strings.Join(validPairs, ";")
+	if len(typeMap) == 0 && len(*pairs) == 0 {
+		return nil, errNoTypes
+	}
+	pairNames = map[[2]string]string{}
+	for _, pair := range getPairs(*pairs) {
+		pairNames[pair] = getPairName(pair[0], pair[1], typeMap)
+	}
+
+	typeMethodsMaps := map[string]map[string]bool{}
+	for k := range typeMap {
+		typeMethodsMaps[k] = methodsMap
+		if methodsStr, ok := typeMethods[k]; ok {
+			typeMethodsMaps[k] = getOptionMethodsMap("-types methods of '"+k+"'", methodsStr)
+		}
+	}
+	checkSupportedMethods(typeMap, typeMethodsMaps, methodsMap)
+	typeNames := getSortedKeys(typeMap)
+	for _, pair := range getPairs(*pairs) {
+		typeNames = append(typeNames, pair[0], pair[1])
+	}
+
+	knownTypes := map[string]bool{}
+	for k, v := range typeMap {
+		knownTypes[k], knownTypes[v] = true, true
+	}
+	for _, pair := range getPairs(*pairs) {
+		knownTypes[getPairName(pair[0], pair[1], typeMap)] = true
+	}
+	knownTypeNames := []string{}
+	for t := range knownTypes {
+		knownTypeNames = append(knownTypeNames, t)
+	}
+	sort.Strings(knownTypeNames)
+	for _, target := range getMapTargets(*mapTargets) {
+		for _, t := range target {
+			if !knownTypes[t] {
+				skipOrFatalf("-map-targets type '%s' is not one of the generated types%s", t, didYouMean(t, knownTypeNames))
+			}
+		}
+	}
+
+	lists := map[string]string{}
+	for k := range typeMap {
+		lists[getListName(k, typeMap)] = k
+	}
+	for _, pair := range getPairs(*pairs) {
+		pairName := getPairName(pair[0], pair[1], typeMap)
+		lists[pairName+*listSuffix] = pairName
+	}
+
+	if collisions := getCollisions(typeMap, typeMethodsMaps, getPairs(*pairs)); len(collisions) > 0 {
+		fatalf("the generated names collide, give the types other names in -types:\n%s", strings.Join(collisions, "\n"))
+	}
+
+	return &generation{typeMap, typeMethodsMaps, typeNames, lists}, nil
+}
+
+// generateSource - generate the source of a file with the lists of the types typeKeys, from typeMap, and of the pairs,
+// with the methods in typeMethodsMaps for the types and the -methods for the pairs. typeNames are all the types which
+// can be targets of the cross-type methods and lists maps the names of all the list types to the types of their members
+func generateSource(typeKeys []string, pairList [][2]string, typeMap map[string]string, typeMethodsMaps map[string]map[string]bool, typeNames []string, lists map[string]string) string {
+	methodsMap := getMethodsMap(*methods)
+	fileMethodsMap := map[string]bool{}
+	for _, k := range typeKeys {
+		for method := range typeMethodsMaps[k] {
+			fileMethodsMap[method] = true
+		}
+	}
+	if len(pairList) > 0 {
+		for method := range methodsMap {
+			fileMethodsMap[method] = true
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `%[4]s%[3]s// Package %[1]s - `+generatedMarker+`%[5]s
+            package %[1]s
+            
+            %[2]s
+			
+            `, *packageName, getImports(fileMethodsMap, typeNames), getBuildConstraint(*buildTags), getFileHeader(*headerFile), getProvenance())
+
+	for _, k1 := range typeKeys {
+		b.WriteString(generate(k1, getListName(k1, typeMap), typeMap, typeMethodsMaps[k1]))
+	}
+
+	for _, pair := range pairList {
+		b.WriteString(generatePair(pair[0], pair[1], typeMap, methodsMap))
+	}
+
+	// the file is formatted once, when all its lists are generated, since formatting it after each type parses the
+	// whole file again each time
+	src := f(b.String())
+
+	if *style != "fungen" {
+		src = getStyledSource(src, lists)
+	}
+
+	if *funcs {
+		src = getFuncsSource(src, lists)
+	}
+
+	if *unexported && !*funcs {
+		src = getUnexportedSource(src, lists)
+	}
+
+	if *receiver != "l" && !*funcs {
+		src = getReceiversSource(src, lists)
+	}
+
+	if *ptrReceiver {
+		src = getPointerReceiversSource(src, lists)
+	}
+
+	if *interfaces {
+		src = getInterfacesSource(src, lists)
+	}
+
+	return getImportsSource(src, getKnownImports(typeNames))
+}
+
+// generatedMarker - the text of the package comment of the generated files which identifies them
+const generatedMarker = "generated by fungen; DO NOT EDIT"
+
+// getBuildConstraint - get the '//go:build' and '// +build' lines for the build constraint expression expr, followed
+// by a blank line, or an empty string if expr is empty
+func getBuildConstraint(expr string) string {
+	if expr == "" {
+		return ""
+	}
+	parsed, err := constraint.Parse("//go:build " + expr)
+	if err != nil {
+		fatalf("-tags '%s' is not a valid build constraint: %s", expr, err)
+	}
+	lines, err := constraint.PlusBuildLines(parsed)
+	if err != nil {
+		fatalf("-tags '%s' cannot be written as '// +build' lines: %s", expr, err)
+	}
+	return "//go:build " + parsed.String() + "\n" + strings.Join(lines, "\n") + "\n\n"
+}
+
+// getFileHeader - get the contents of the header file path followed by a blank line, or an empty string if path is
+// empty. The header file must only contain comments
+func getFileHeader(path string) string {
+	if path == "" {
+		return ""
+	}
+	header, err := ioutil.ReadFile(path)
+	if err != nil {
+		fatalf("reading -header-file: %s", err)
+	}
+	src := strings.TrimRight(string(header), "\n") + "\n\n"
+	file, err := parser.ParseFile(token.NewFileSet(), path, src+"package main\n", parser.ParseComments)
+	if err != nil || len(file.Comments) == 0 {
+		fatalf("-header-file '%s' must only contain comments", path)
+	}
+	return src
+}
+
+// modeFlags - the flags which only change what is done with the generated files, which are left out of their
+// provenance
+var modeFlags = map[string]bool{
+	"check":       true,
+	"diff":        true,
+	"plan":        true,
+	"v":           true,
+	"q":           true,
+	"force":       true,
+	"force-write": true,
+	"watch":       true,
+	"cpuprofile":  true,
+	"memprofile":  true,
+	"test":        true,
+	"vet":         true,
+}
+
+// getCommandLine - get the fungen command line with the flags set in flags, except the mode flags and '-filename -',
+// which writes to the standard output, in lexicographic order. The values are quoted when needed, so that the command
+// line can be used in a go:generate directive
+func getCommandLine(flags *flag.FlagSet) string {
+	args := []string{"fungen"}
+	flags.Visit(func(fl *flag.Flag) {
+		if modeFlags[fl.Name] || fl.Name == "filename" && fl.Value.String() == "-" {
+			return
+		}
+		if getter, ok := fl.Value.(flag.Getter); ok {
+			if _, ok := getter.Get().(bool); ok {
+				args = append(args, "-"+fl.Name+"="+fl.Value.String())
+				return
+			}
+		}
+		value := fl.Value.String()
+		if value == "" || strings.ContainsAny(value, " \t\"'`$\\*?[]{}()<>|&;!#~") {
+			value = strconv.Quote(value)
+		}
+		args = append(args, "-"+fl.Name, value)
+	})
+	return strings.Join(args, " ")
+}
+
+// getVersion - get the version of fungen from its build information, or 'devel' when it was not built from a
+// released module version
+func getVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "devel"
+}
+
+// getVersionInfo - get the description of the version of fungen, along with the Go version which built it and the
+// methods it supports
+func getVersionInfo() string {
+	methods, optInMethods := []string{}, []string{}
+	generators.Each(func(gen Generator) {
+		if gen.OptIn {
+			optInMethods = append(optInMethods, gen.Name)
+		} else {
+			methods = append(methods, gen.Name)
+		}
+	})
+	return fmt.Sprintf("fungen %s (%s)\nmethods: %s\nopt-in methods: %s\n", getVersion(), runtime.Version(), strings.Join(methods, ","), strings.Join(optInMethods, ","))
+}
+
+// getProvenance - get the comment lines, appended to the package comment, which record the fungen version and command
+// line which generated the file, along with the go:generate directive generating it again. In -append mode, the
+// sections of the file record their command lines instead
+func getProvenance() string {
+	if *appendMode {
+		return ""
+	}
+	where := "from its directory or add this directive to a file of the package"
+	if *subpackage != "" {
+		where = "from the directory of the parent package or add this directive to one of its files"
+	}
+	return fmt.Sprintf(`
+//
+// Generated by fungen %[1]s with:
+//
+//	%[2]s
+//
+// To generate it again, run the command above %[3]s:
+//
+//	//go:generate %[2]s`, getVersion(), commandLine, where)
+}
+
+// getSortedKeys - get the keys of the type map m in lexicographic order, so that the types are always generated in
+// the same order
+func getSortedKeys(m map[string]string) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// getOutputDir - get the directory of the generated files, which is also the directory of the target package
+func getOutputDir() string {
+	if *outputDir != "" {
+		return *outputDir
+	}
+	return filepath.Dir(*outputName)
+}
+
+// getSplitFileName - get the name of the file generated for the type named typeName with -split, eg. 'fungen_user.go'
+func getSplitFileName(typeName string) string {
+	return "fungen_" + strings.ToLower(typeName) + ".go"
+}
+
+// getOutputPath - get the path of the generated file fileName, in the output directory if -outdir is set
+func getOutputPath(fileName string) string {
+	if *subpackage != "" && fileName != "-" {
+		return filepath.Join(getOutputDir(), *subpackage, filepath.Base(fileName))
+	}
+	if *outputDir != "" && fileName != "-" {
+		return filepath.Join(*outputDir, filepath.Base(fileName))
+	}
+	return fileName
+}
+
+// getStaleness - compare the generated source src with the file fileName and describe how it differs, or return ""
+// if the file is up to date
+func getStaleness(fileName, src string) string {
+	content, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return fileName + " is missing"
+	} else if err != nil {
+		fatalf("reading the generated file '%s' for -check: %s", fileName, err)
+	}
+	if string(content) == src {
+		return ""
+	}
+
+	lines, srcLines := strings.Split(string(content), "\n"), strings.Split(src, "\n")
+	i := 0
+	for i < len(lines) && i < len(srcLines) && lines[i] == srcLines[i] {
+		i++
+	}
+	return fmt.Sprintf("%s differs from line %d (%d lines, %d expected)", fileName, i+1, len(lines), len(srcLines))
+}
+
+// writeOutput - write the generated source src to the file fileName in the output directory, or to the standard
+// output if fileName is '-', and return the error writing it, if any. The file is not written if it is already up to
+// date, unless -force-write is set
+func writeOutput(fileName, src string) error {
+	fileName = getOutputPath(fileName)
+
+	if *testrun {
+		fmt.Println(fileName)
+		fmt.Println(src)
+	} else if fileName == "-" {
+		if _, err := os.Stdout.WriteString(src); err != nil {
+			return fmt.Errorf("writing the generated file '%s': %s", fileName, err)
+		}
+	} else {
+		if !*forceWrite && isUpToDate(fileName, src) {
+			verbosef("%s is up to date", fileName)
+			return nil
+		}
+		if *subpackage != "" {
+			if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+				return fmt.Errorf("creating the directory of the subpackage '%s': %s", filepath.Dir(fileName), err)
+			}
+		}
+		if err := writeFile(fileName, src); err != nil {
+			return fmt.Errorf("writing the generated file '%s': %s", fileName, err)
+		}
+		verbosef("wrote %s", fileName)
+	}
+	return nil
+}
+
+func f(s string) string {
+	formatted, err := format.Source([]byte(s))
+	if err != nil {
+		fatalf("the generated code cannot be formatted, which may come from a -templates file or a type: %s", err)
+	}
+	return string(formatted)
+}
+
+func getFileNameForTypes(t string, m map[string]string) string {
+	if len(m) == 0 {
+		return t
+	}
+	s := t
+	for k, v := range m {
+		if t == k {
+			continue
+		}
+		s += "_" + v
+	}
+	return s
+}
+
+func getTypeMap(targets string) map[string]string {
+	m := map[string]string{}
+	if targets == "" {
+		return m
+	}
+
+	sep := byte(',')
+	if len(splitTopLevel(targets, ';')) > 1 {
+		sep = ';'
+	}
+	targetParts := splitTopLevel(targets, sep)
+	offsets := getPartOffsets(targetParts)
+	for i, t := range targetParts {
+		traits := splitTopLevel(t, '+')
+		tParts := splitTopLevel(traits[0], ':')
+		typeName := unquote(tParts[0])
+		checkTypeExpr("-types", targets, offsets[i], tParts[0])
+		if len(traits) > 1 {
+			addTypeTraits(typeName, traits[1:])
+		}
+		if len(tParts) == 1 || unquote(tParts[1]) == "" {
+			m[typeName] = typeName
+		} else {
+			m[typeName] = unquote(tParts[1])
+		}
+		if len(tParts) > 2 && unquote(tParts[2]) != "" {
+			listNames[typeName] = unquote(tParts[2])
+		}
+		if len(tParts) > 3 {
+			typeMethods[typeName] = strings.Replace(unquote(tParts[3]), " ", "", -1)
+			if typeMethods[typeName] == "all" {
+				typeMethods[typeName] = ""
+			}
+		}
+	}
+
+	return m
+}
+
+// getImports - get the import declaration for the packages needed by the selected methods and by the types typeNames
+func getImports(methodsMap map[string]bool, typeNames []string) string {
+	specs := map[string]bool{}
+	generators.Filter(func(gen Generator) bool {
+		selectedMethod, _ := methodsMap[gen.Name]
+		return selectedMethod
+	}).Each(func(gen Generator) {
+		if gen.NeedSync {
+			specs[strconv.Quote("sync")] = true
+		}
+		if gen.Supports != nil && !gen.NeedMapToMap && !isSupportedByAny(gen, typeNames) {
+			return
+		}
+		for _, importPath := range gen.Imports {
+			specs[strconv.Quote(importPath)] = true
+		}
+	})
+
+	importPathsMap := getImportPathsMap(*importPaths)
+	for _, typeName := range typeNames {
+		for _, name := range getPackageNames(typeName) {
+			specs[getImportSpec(name, importPathsMap)] = true
+		}
+	}
+
+	if len(specs) == 0 {
+		return ""
+	}
+
+	sorted := []string{}
+	for spec := range specs {
+		sorted = append(sorted, spec)
+	}
+	sort.Strings(sorted)
+
+	var imports strings.Builder
+	imports.WriteString("import (\n")
+	for _, spec := range sorted {
+		imports.WriteString(spec + "\n")
+	}
+	imports.WriteString(")")
+	return imports.String()
+}
+
+// getKnownImports - get the import specs, by package name, of the packages which the generated source may use: the
+// packages imported by any generator or method template and the packages of the types typeNames
+func getKnownImports(typeNames []string) map[string]string {
+	known := map[string]string{"sync": strconv.Quote("sync")}
+	generators.Each(func(gen Generator) {
+		for _, importPath := range gen.Imports {
+			known[path.Base(importPath)] = strconv.Quote(importPath)
+		}
+	})
+	for _, importPath := range append(getTemplateImports(), getGoImports()...) {
+		known[path.Base(importPath)] = strconv.Quote(importPath)
+	}
+
+	importPathsMap := getImportPathsMap(*importPaths)
+	for _, typeName := range typeNames {
+		for _, name := range getPackageNames(typeName) {
+			known[name] = getImportSpec(name, importPathsMap)
+		}
+	}
+	return known
+}
+
+// isSupportedByAny - whether the generator gen supports any of the types in typeNames
+func isSupportedByAny(gen Generator, typeNames []string) bool {
+	for _, typeName := range typeNames {
+		if gen.Supports(typeName, "") {
+			return true
+		}
+	}
+	return false
+}
+
+// getImportSpec - get the import spec of the package name, whose path is given in importPathsMap or resolved from the
+// target package
+func getImportSpec(name string, importPathsMap map[string]string) string {
+	importPath, ok := importPathsMap[name]
+	if !ok {
+		importPath = resolveImportPath(name)
+	}
+	if path.Base(importPath) == name {
+		return strconv.Quote(importPath)
+	}
+	return name + " " + strconv.Quote(importPath)
+}
+
+// getImportPathsMap - get the import paths of packages by name from the -import option
+func getImportPathsMap(importPathsStr string) map[string]string {
+	m := map[string]string{}
+	if importPathsStr == "" {
+		return m
+	}
+
+	for _, i := range strings.Split(importPathsStr, ",") {
+		iParts := strings.Split(i, "=")
+		if len(iParts) != 2 {
+			fatalf("-import value '%s' is not of the form name=path", i)
+		}
+		m[iParts[0]] = iParts[1]
+	}
+
+	return m
+}
+
+// getPairs - get the pairs of types from the -pairs option
+func getPairs(pairsStr string) [][2]string {
+	result := [][2]string{}
+	if pairsStr == "" {
+		return result
+	}
+
+	pairList := splitTopLevel(pairsStr, ';')
+	offsets := getPartOffsets(pairList)
+	for i, p := range pairList {
+		pParts := splitTopLevel(p, ',')
+		if len(pParts) != 2 {
+			fatalf("-pairs: '%s' is not a pair of types, eg. 'int,string'%s", p, getTokenPointer(pairsStr, offsets[i], offsets[i]+len(p)))
+		}
+		first, second := unquote(pParts[0]), unquote(pParts[1])
+		checkTypeExpr("-pairs", pairsStr, offsets[i], pParts[0])
+		checkTypeExpr("-pairs", pairsStr, offsets[i]+len(pParts[0])+1, pParts[1])
+		result = append(result, [2]string{first, second})
+	}
+
+	return result
+}
+
+// getListName - get the name of the list type generated for typeName, either given in -types or made of its name and
+// the -suffix option
+func getListName(typeName string, m map[string]string) string {
+	listName, ok := listNames[typeName]
+	if !ok {
+		listName = getTypeName(typeName, m) + *listSuffix
+	}
+	if listName == typeName {
+		fatalf("the list type for '%s' would have the same name as the type, give it another name with %s:Name", typeName, typeName)
+	}
+	return listName
+}
+
+// isFlagSet - whether the flag name was set on the command line or by the configuration file
+func isFlagSet(name string) bool {
+	set := false
+	flagSet.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
+
+// getDefaultPackageName - get the name of the package of the generated file when -package is not set, which is
+// $GOPACKAGE when run by go generate, or the package of the files in the directory of the generated file, or main
+func getDefaultPackageName() string {
+	if name := os.Getenv("GOPACKAGE"); name != "" {
+		return name
+	}
+	loadPackageFiles()
+	if len(packageFiles) > 0 {
+		return packageFiles[0].Name.Name
+	}
+	return "main"
+}
+
+// getMethodsMap - get selected methods from -methods option, or return all methods which are not opt-in, without the
+// methods of the -exclude-methods option
+func getMethodsMap(methodsStr string) map[string]bool {
+	return getOptionMethodsMap("-methods", methodsStr)
+}
+
+// getOptionMethodsMap - get the methods map of methodsStr, given in the option described by option for the errors
+func getOptionMethodsMap(option, methodsStr string) map[string]bool {
+	validMethods := map[string]bool{}
+	generators.Each(func(gen Generator) {
+		validMethods[gen.Name] = !(*funcs && gen.NeedListType)
+	})
+
+	result := map[string]bool{}
+	if methodsStr == "" {
+		generators.Each(func(gen Generator) {
+			if !gen.OptIn && !(*funcs && gen.NeedListType) {
+				result[gen.Name] = true
+			}
+		})
+	} else {
+		methodList := strings.Split(methodsStr, ",")
+		offsets := getPartOffsets(methodList)
+		for i, method := range methodList {
+			pointer := getTokenPointer(methodsStr, offsets[i], offsets[i]+len(method))
+			if valid, ok := validMethods[method]; !ok {
+				skipOrFatalf("%s: '%s' is not a valid method%s%s", option, method, didYouMean(method, getMethodNames()), pointer)
+				continue
+			} else if !valid {
+				skipOrFatalf("%s: '%s' cannot be used with -funcs, since it needs a list type%s", option, method, pointer)
+				continue
+			}
+			result[method] = true
+		}
+	}
+
+	if *excluded != "" {
+		excludedList := strings.Split(*excluded, ",")
+		offsets := getPartOffsets(excludedList)
+		for i, method := range excludedList {
+			if _, ok := validMethods[method]; !ok {
+				skipOrFatalf("-exclude-methods: '%s' is not a valid method%s%s", method, didYouMean(method, getMethodNames()), getTokenPointer(*excluded, offsets[i], offsets[i]+len(method)))
+			}
+			delete(result, method)
+		}
+	}
+
+	return result
+}
+
+func generate(typeName, listname string, m map[string]string, methodsMap map[string]bool) string {
+	var code strings.Builder
+	if !*funcs && !existLists[listname] {
+		fmt.Fprintf(&code, `
+            
+            // %[2]s is the type for a list that holds members of type %[1]s
+            type %[2]s []%[1]s
+            `, typeName, listname)
+	}
+
+	start := time.Now()
+	generated := map[string]bool{}
+	eachMethod(typeName, m, methodsMap, func(gen Generator, targetType, targetTypeName string) {
+		code.WriteString(getMethodSource(gen, listname, typeName, targetType, targetTypeName))
+		generated[gen.Name] = true
+		verbosef("generated %s.%s", listname, getMethodName(gen, targetTypeName))
+	})
+	generators.Each(func(gen Generator) {
+		if _, ok := methodsMap[gen.Name]; ok && !generated[gen.Name] {
+			verbosef("skipped %s.%s, which does not support the members of type %s", listname, gen.Name, typeName)
+			if hasExplicitMethods(typeName) {
+				skipf("%s.%s is not generated, it does not support the members of type %s", listname, gen.Name, typeName)
+			}
+		}
+	})
+	verbosef("generated %s, the list of %s, in %s", listname, typeName, time.Since(start))
+
+	return code.String()
+}
+
+// eachMethod - call fn with each generator in methodsMap supporting typeName and, for the cross-type generators, with
+// each of the target types in m along with its name, which is empty when the target is typeName itself
+func eachMethod(typeName string, m map[string]string, methodsMap map[string]bool, fn func(gen Generator, targetType, targetTypeName string)) {
+	generators.Filter(func(gen Generator) bool {
+		_, ok := methodsMap[gen.Name]
+		return ok
+	}).Each(func(gen Generator) {
+		if gen.NeedMapToMap {
+			for _, k := range getSortedKeys(m) {
+				if *noCrossMaps && k != typeName || !isMapTarget(typeName, k, m) {
+					continue
+				}
+				if gen.Supports != nil && !gen.Supports(typeName, k) {
+					continue
+				}
+
+				targetTypeName := getTypeName(k, m)
+				if k == typeName {
+					targetTypeName = ""
+				}
+
+				fn(gen, k, targetTypeName)
+			}
+		} else if gen.Supports == nil || gen.Supports(typeName, "") {
+			fn(gen, "", "")
+		}
+	})
+}
+
+// generatePair - generate the pair struct for the types first and second along with its list type.
+// The list type gets the selected methods, with the types in m as targets of the cross-type methods
+func generatePair(first, second string, m map[string]string, methodsMap map[string]bool) string {
+	pairName := getPairName(first, second, m)
+	code := fmt.Sprintf(`
+            // %[1]s is a pair of values of type %[2]s and %[3]s
+            type %[1]s struct {
+                First  %[2]s
+                Second %[3]s
+            }
+
+            // %[4]s returns a %[1]s holding the given values
+            func %[4]s(first %[2]s, second %[3]s) %[1]s {
+                return %[1]s{first, second}
+            }
+
+            // Values is a method on %[1]s that returns both members of the pair
+            func (p %[1]s) Values() (%[2]s, %[3]s) {
+                return p.First, p.Second
+            }
+
+            // WithFirst is a method on %[1]s that returns a copy of the pair with the first member replaced by the given value
+            func (p %[1]s) WithFirst(first %[2]s) %[1]s {
+                p.First = first
+                return p
+            }
+
+            // WithSecond is a method on %[1]s that returns a copy of the pair with the second member replaced by the given value
+            func (p %[1]s) WithSecond(second %[3]s) %[1]s {
+                p.Second = second
+                return p
+            }
+            `, pairName, first, second, getConstructorName(pairName))
+
+	pairMap := getPairMap(pairName, m)
+	return code + generate(pairName, getListName(pairName, pairMap), pairMap, methodsMap)
+}
+
+// getPairMap - get the type map m along with the pair type pairName, which is named after itself
+func getPairMap(pairName string, m map[string]string) map[string]string {
+	pairMap := map[string]string{pairName: pairName}
+	for k, v := range m {
+		pairMap[k] = v
+	}
+	return pairMap
+}
+
+// getMapTargets - get the source and target type pairs from the -map-targets option, eg. 'User>string,User>int'
+func getMapTargets(targetsStr string) [][2]string {
+	result := [][2]string{}
+	if targetsStr == "" {
+		return result
+	}
+
+	for _, t := range splitTopLevel(targetsStr, ',') {
+		tParts := splitTopLevel(t, '>')
+		if len(tParts) != 2 {
+			fatalf("-map-targets value '%s' is not a source>target pair of types", t)
+		}
+		result = append(result, [2]string{unquote(tParts[0]), unquote(tParts[1])})
+	}
+
+	return result
+}
+
+// isMapTarget - whether the cross-type methods from typeName to targetType are selected by the -map-targets option,
+// which can name the types by their names in m. The methods from a type to itself are always selected
+func isMapTarget(typeName, targetType string, m map[string]string) bool {
+	if *mapTargets == "" || typeName == targetType {
+		return true
+	}
+
+	for _, t := range getMapTargets(*mapTargets) {
+		if (t[0] == typeName || t[0] == m[typeName]) && (t[1] == targetType || t[1] == m[targetType]) {
+			return true
+		}
+	}
+	return false
+}
+
+// getTargetListName - get the name of the list type of targetType for the cross-type methods, named targetTypeName
+func getTargetListName(targetType, targetTypeName string) string {
+	if listName, ok := listNames[targetType]; ok {
+		return listName
+	}
+	return targetTypeName + *listSuffix
+}
+
+// getPairName - get the name of the pair struct generated for the types first and second
+func getPairName(first, second string, m map[string]string) string {
+	return getTypeName(first, m) + getExportedName(getTypeName(second, m)) + "Pair"
+}
+
+// hasPair - whether the pair of the types first and second is listed in -pairs
+func hasPair(first, second string) bool {
+	_, ok := pairNames[[2]string{first, second}]
+	return ok
+}
+
+// getPairTypeName - get the name of the pair struct generated for the types first and second in -pairs
+func getPairTypeName(first, second string) string {
+	return pairNames[[2]string{first, second}]
+}
+
+// getPairListName - get the name of the list type of the pair struct generated for the types first and second
+func getPairListName(first, second string) string {
+	return getPairTypeName(first, second) + *listSuffix
+}
+
+var mapTemplate = parseMethodTemplate("Map", `
+        // Map{{.TargetName}} is a method on {{.List}} that takes a function of type {{.Type}} -> {{.TargetType}} and applies it to every member of {{.List}}
+        func (l {{.List}}) Map{{.TargetName}}(f func({{.Type}}) {{.TargetType}}) {{.TargetList}} {
+            l2 := make({{.TargetList}}, len(l))
+            for i, t := range l {
+                l2[i] = f(t)
+            }
+            return l2
+        }
+        `)
+
+func getMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(mapTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var pMapTemplate = parseMethodTemplate("PMap", `
+        // PMap{{.TargetName}} is similar to Map{{.TargetName}} except that it executes the function on each member in parallel.
+        func (l {{.List}}) PMap{{.TargetName}}(f func({{.Type}}) {{.TargetType}}) {{.TargetList}} {
+            wg := sync.WaitGroup{}
+            l2 := make({{.TargetList}}, len(l))
+            for i, t := range l {
+                wg.Add(1)
+                go func(i int, t {{.Type}}){
+                    l2[i] = f(t)
+                    wg.Done()
+                }(i, t)
+            }
+            wg.Wait()
+            return l2
+        }
+        `)
+
+func getPMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(pMapTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var filterTemplate = parseMethodTemplate("Filter", `
+        // Filter is a method on {{.List}} that takes a function of type {{.Type}} -> bool returns a list of type {{.List}} which contains all members from the original list for which the function returned true
+        func (l {{.List}}) Filter(f func({{.Type}}) bool) {{.List}} {
+            l2 := make({{.List}}, 0, len(l))
+            for _, t := range l {
+                if f(t) {
+                    l2 = append(l2, t)
+                }
+            }
+            return l2
+        }
+        `)
+
+func getFilterFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(filterTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var pFilterTemplate = parseMethodTemplate("PFilter", `
+        // PFilter is similar to the Filter method except that the filter is applied to all the elements in parallel. The order of resulting elements cannot be guaranteed. 
+        func (l {{.List}}) PFilter(f func({{.Type}}) bool) {{.List}} {
+            wg := sync.WaitGroup{}
+            mutex := sync.Mutex{}
+            l2 := make({{.List}}, 0, len(l))
+            for _, t := range l {
+                wg.Add(1)
+                go func(t {{.Type}}){
+                    if f(t) {
+                        mutex.Lock()
+                        l2 = append(l2, t)
+                        mutex.Unlock()
+                    }            
+                    wg.Done()
+                }(t)
+            }
+            wg.Wait()
+            return l2
+        }
+        `)
+
+func getPFilterFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(pFilterTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var eachTemplate = parseMethodTemplate("Each", `
+        // Each is a method on {{.List}} that takes a function of type {{.Type}} -> void and applies the function to each member of the list and then returns the original list.
+        func (l {{.List}}) Each(f func({{.Type}})) {{.List}} {
+            for _, t := range l {
+                f(t) 
+            }
+            return l
+        }
+        `)
+
+func getEachFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(eachTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var eachITemplate = parseMethodTemplate("EachI", `
+        // EachI is a method on {{.List}} that takes a function of type (int, {{.Type}}) -> void and applies the function to each member of the list and then returns the original list. The int parameter to the function is the index of the element.
+        func (l {{.List}}) EachI(f func(int, {{.Type}})) {{.List}} {
+            for i, t := range l {
+                f(i, t) 
+            }
+            return l
+        }
+        `)
+
+func getEachIFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(eachITemplate, listName, typeName, targetType, targetTypeName)
+}
+
+// getSliceExpr - get the expression slicing the list l from low to high, which is a full slice expression limiting the
+// capacity of the result to its length if the -full-slice option is set
+func getSliceExpr(low, high string) string {
+	if !*fullSlice {
+		return "l[" + low + ":" + high + "]"
+	}
+	if high == "" {
+		high = "len(l)"
+	}
+	return "l[" + low + ":" + high + ":" + high + "]"
+}
+
+var dropWhileTemplate = parseMethodTemplate("DropWhile", `{{if .Copy}}
+        // DropWhile is a method on {{.List}} that takes a function of type {{.Type}} -> bool and returns a list of type {{.List}} which excludes the first members from the original list for which the function returned true. The result is a copy which does not share its backing array with the original list.
+        func (l {{.List}}) DropWhile(f func({{.Type}}) bool) {{.List}} {
+            for i, t := range l {
+                if !f(t) {
+                    l2 := make({{.List}}, len(l)-i)
+                    copy(l2, l[i:])
+                    return l2
+                }
+            }
+            var l2 {{.List}}
+            return l2
+        }
+        {{else}}
+        // DropWhile is a method on {{.List}} that takes a function of type {{.Type}} -> bool and returns a list of type {{.List}} which excludes the first members from the original list for which the function returned true
+        func (l {{.List}}) DropWhile(f func({{.Type}}) bool) {{.List}} {
+            for i, t := range l {
+                if !f(t) {
+                    return {{sliceExpr "i" ""}}
+                }
+            }
+            var l2 {{.List}}
+            return l2
+        }
+        {{end}}`)
+
+func getDropWhileFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(dropWhileTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var takeWhileTemplate = parseMethodTemplate("TakeWhile", `{{if .Copy}}
+        // TakeWhile is a method on {{.List}} that takes a function of type {{.Type}} -> bool and returns a list of type {{.List}} which includes only the first members from the original list for which the function returned true. The result is a copy which does not share its backing array with the original list.
+        func (l {{.List}}) TakeWhile(f func({{.Type}}) bool) {{.List}} {
+            for i, t := range l {
+                if !f(t) {
+                    l = l[:i]
+                    break
+                }
+            }
+            l2 := make({{.List}}, len(l))
+            copy(l2, l)
+            return l2
+        }
+        {{else}}
+        // TakeWhile is a method on {{.List}} that takes a function of type {{.Type}} -> bool and returns a list of type {{.List}} which includes only the first members from the original list for which the function returned true
+        func (l {{.List}}) TakeWhile(f func({{.Type}}) bool) {{.List}} {
+            for i, t := range l {
+                if !f(t) {
+                    return {{sliceExpr "" "i"}}
+                }
+            }
+            return l
+        }
+        {{end}}`)
+
+func getTakeWhileFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(takeWhileTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var takeTemplate = parseMethodTemplate("Take", `{{if .Copy}}
+        // Take is a method on {{.List}} that takes an integer n and returns the first n elements of the original list. If the list contains fewer than n elements then the entire list is returned. The result is a copy which does not share its backing array with the original list.
+        func (l {{.List}}) Take(n int) {{.List}} {
+            if len(l) >= n {
+                l = l[:n]
+            }
+            l2 := make({{.List}}, len(l))
+            copy(l2, l)
+            return l2
+        }
+        {{else}}
+        // Take is a method on {{.List}} that takes an integer n and returns the first n elements of the original list. If the list contains fewer than n elements then the entire list is returned.
+        func (l {{.List}}) Take(n int) {{.List}} {
+            if len(l) >= n {
+                return {{sliceExpr "" "n"}}
+            }
+            return l
+        }
+        {{end}}`)
+
+func getTakeFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(takeTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var dropTemplate = parseMethodTemplate("Drop", `{{if .Copy}}
+        // Drop is a method on {{.List}} that takes an integer n and returns all but the first n elements of the original list. If the list contains fewer than n elements then an empty list is returned. The result is a copy which does not share its backing array with the original list.
+        func (l {{.List}}) Drop(n int) {{.List}} {
+            if len(l) >= n {
+                l2 := make({{.List}}, len(l)-n)
+                copy(l2, l[n:])
+                return l2
+            }
+            var l2 {{.List}}
+            return l2
+        }
+        {{else}}
+        // Drop is a method on {{.List}} that takes an integer n and returns all but the first n elements of the original list. If the list contains fewer than n elements then an empty list is returned.
+        func (l {{.List}}) Drop(n int) {{.List}} {
+            if len(l) >= n {
+                return {{sliceExpr "n" ""}}
+            }
+            var l2 {{.List}}
+            return l2
+        }
+        {{end}}`)
+
+func getDropFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(dropTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var reduceTemplate = parseMethodTemplate("Reduce", `
+        // Reduce is a method on {{.List}} that takes a function of type ({{.Type}}, {{.Type}}) -> {{.Type}} and returns a {{.Type}} which is the result of applying the function to all members of the original list starting from the first member
+        func (l {{.List}}) Reduce(t1 {{.Type}}, f func({{.Type}}, {{.Type}}) {{.Type}}) {{.Type}} {
+            for _, t := range l {
+                t1 = f(t1, t)
+            }
+            return t1
+        }
+        `)
+
+func getReduceFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(reduceTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var reduceRightTemplate = parseMethodTemplate("ReduceRight", `
+        // ReduceRight is a method on {{.List}} that takes a function of type ({{.Type}}, {{.Type}}) -> {{.Type}} and returns a {{.Type}} which is the result of applying the function to all members of the original list starting from the last member
+        func (l {{.List}}) ReduceRight(t1 {{.Type}}, f func({{.Type}}, {{.Type}}) {{.Type}}) {{.Type}} {
+            for i := len(l) - 1; i >= 0; i-- {
+                t := l[i]
+                t1 = f(t, t1)
+            }
+            return t1
+        }
+        `)
+
+func getReduceRightFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(reduceRightTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var allTemplate = parseMethodTemplate("All", `
+        // All is a method on {{.List}} that returns true if all the members of the list satisfy a function or if the list is empty. 
+        func (l {{.List}}) All(f func({{.Type}}) bool) bool {
+            for _, t := range l {
+                if !f(t) {
+                    return false
+                }
+            }
+            return true
+        }
+        `)
+
+func getAllFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(allTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var anyTemplate = parseMethodTemplate("Any", `
+        // Any is a method on {{.List}} that returns true if at least one member of the list satisfies a function. It returns false if the list is empty. 
+        func (l {{.List}}) Any(f func({{.Type}}) bool) bool {
+            {{- if .Slices}}
+            return slices.ContainsFunc(l, f)
+            {{- else}}
+            for _, t := range l {
+                if f(t) {
+                    return true
+                }
+            }
+            return false
+            {{- end}}
+        }
+        `)
+
+func getAnyFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(anyTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+// filterMapToTemplate - FilterMapTo is only generated for the other types: within the same type, Filter and MapInPlace
+// suffice
+var filterMapToTemplate = parseMethodTemplate("FilterMapTo", `{{if .TargetName}}
+        // FilterMapTo{{.TargetName}} is a method on {{.List}} that takes a function of type {{.Type}} -> ({{.TargetType}}, bool) and returns a list of type {{.TargetList}} which contains the results of the function for all members of the original list for which it returned true, in a single loop
+        func (l {{.List}}) FilterMapTo{{.TargetName}}(f func({{.Type}}) ({{.TargetType}}, bool)) {{.TargetList}} {
+            l2 := {{.TargetList}}{}
+            for _, t := range l {
+                if u, ok := f(t); ok {
+                    l2 = append(l2, u)
+                }
+            }
+            return l2
+        }
+        {{end}}`)
+
+func getFilterMapToFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(filterMapToTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var mapNotNilTemplate = parseMethodTemplate("MapNotNil", `
+        // MapNotNil{{.TargetName}} is a method on {{.List}} that takes a function of type {{.Type}} -> *{{.TargetType}} and returns a list of type {{.TargetList}} which contains the values pointed to by the results of the function which are not nil
+        func (l {{.List}}) MapNotNil{{.TargetName}}(f func({{.Type}}) *{{.TargetType}}) {{.TargetList}} {
+            l2 := {{.TargetList}}{}
+            for _, t := range l {
+                if u := f(t); u != nil {
+                    l2 = append(l2, *u)
+                }
+            }
+            return l2
+        }
+        `)
+
+func getMapNotNilFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(mapNotNilTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var flatMapTemplate = parseMethodTemplate("FlatMap", `
+        // FlatMap{{.TargetName}} is a method on {{.List}} that takes a function of type {{.Type}} -> {{.TargetList}} and returns the concatenation of the lists returned by the function for every member of {{.List}}
+        func (l {{.List}}) FlatMap{{.TargetName}}(f func({{.Type}}) {{.TargetList}}) {{.TargetList}} {
+            l2 := {{.TargetList}}{}
+            for _, t := range l {
+                l2 = append(l2, f(t)...)
+            }
+            return l2
+        }
+        `)
+
+func getFlatMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(flatMapTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+// foldTemplate - Fold is only generated for the other types: within the same type, Reduce suffices
+var foldTemplate = parseMethodTemplate("Fold", `{{if .TargetName}}
+        // Fold{{.TargetName}} is a method on {{.List}} that takes a seed of type {{.TargetType}} and a function of type ({{.TargetType}}, {{.Type}}) -> {{.TargetType}} and returns a {{.TargetType}} which is the result of applying the function to the seed and all members of the original list starting from the first member
+        func (l {{.List}}) Fold{{.TargetName}}(seed {{.TargetType}}, f func({{.TargetType}}, {{.Type}}) {{.TargetType}}) {{.TargetType}} {
+            for _, t := range l {
+                seed = f(seed, t)
+            }
+            return seed
+        }
+        {{end}}`)
+
+func getFoldFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(foldTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var zipTemplate = parseMethodTemplate("Zip", `
+        // Zip{{.TargetName}} is a method on {{.List}} that takes a list of type {{.TargetList}} and a function of type ({{.Type}}, {{.TargetType}}) -> {{.Type}} and returns a list of type {{.List}} which contains the results of the function for the members of both lists at the same index. The result is as long as the shorter list
+        func (l {{.List}}) Zip{{.TargetName}}(other {{.TargetList}}, f func({{.Type}}, {{.TargetType}}) {{.Type}}) {{.List}} {
+            n := len(l)
+            if len(other) < n {
+                n = len(other)
+            }
+            l2 := make({{.List}}, n)
+            for i := range l2 {
+                l2[i] = f(l[i], other[i])
+            }
+            return l2
+        }
+        `)
+
+func getZipFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(zipTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var zipPairsTemplate = parseMethodTemplate("ZipPairs", `
+        // ZipPairs{{.TargetName}} is a method on {{.List}} that takes a list of type {{.TargetList}} and returns a list of type {{pairList .Type .TargetType}} pairing the members of both lists at the same index. The result is as long as the shorter list
+        func (l {{.List}}) ZipPairs{{.TargetName}}(other {{.TargetList}}) {{pairList .Type .TargetType}} {
+            n := len(l)
+            if len(other) < n {
+                n = len(other)
+            }
+            l2 := make({{pairList .Type .TargetType}}, n)
+            for i := range l2 {
+                l2[i] = {{pairName .Type .TargetType}}{l[i], other[i]}
+            }
+            return l2
+        }
+        `)
+
+func getZipPairsFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(zipPairsTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var enumerateTemplate = parseMethodTemplate("Enumerate", `
+        // Enumerate is a method on {{.List}} that returns a list of type {{pairList "int" .Type}} pairing the index of each member with the member
+        func (l {{.List}}) Enumerate() {{pairList "int" .Type}} {
+            l2 := make({{pairList "int" .Type}}, len(l))
+            for i, t := range l {
+                l2[i] = {{pairName "int" .Type}}{i, t}
+            }
+            return l2
+        }
+        `)
+
+func getEnumerateFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(enumerateTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var runLengthEncodeTemplate = parseMethodTemplate("RunLengthEncode", `
+        // RunLengthEncode is a method on {{.List}} that returns a list of type {{pairList .Type "int"}} pairing each run of equal consecutive members with its length
+        func (l {{.List}}) RunLengthEncode() {{pairList .Type "int"}} {
+            l2 := {{pairList .Type "int"}}{}
+            for i, t := range l {
+                if i > 0 && l[i-1] == t {
+                    l2[len(l2)-1].Second++
+                    continue
+                }
+                l2 = append(l2, {{pairName .Type "int"}}{t, 1})
+            }
+            return l2
+        }
+        `)
+
+func getRunLengthEncodeFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(runLengthEncodeTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var groupByTemplate = parseMethodTemplate("GroupBy", `
+        // GroupBy{{.TargetName}} is a method on {{.List}} that takes a function of type {{.Type}} -> {{.TargetType}} and returns a map from the results of the function to the lists of the members of {{.List}} for which it returned them, in their original order
+        func (l {{.List}}) GroupBy{{.TargetName}}(f func({{.Type}}) {{.TargetType}}) map[{{.TargetType}}]{{.List}} {
+            groups := map[{{.TargetType}}]{{.List}}{}
+            for _, t := range l {
+                key := f(t)
+                groups[key] = append(groups[key], t)
+            }
+            return groups
+        }
+        `)
+
+func getGroupByFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(groupByTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var countByTemplate = parseMethodTemplate("CountBy", `
+        // CountBy{{.TargetName}} is a method on {{.List}} that takes a function of type {{.Type}} -> {{.TargetType}} and returns a map from the results of the function to the number of members of {{.List}} for which it returned them
+        func (l {{.List}}) CountBy{{.TargetName}}(f func({{.Type}}) {{.TargetType}}) map[{{.TargetType}}]int {
+            counts := map[{{.TargetType}}]int{}
+            for _, t := range l {
+                counts[f(t)]++
+            }
+            return counts
+        }
+        `)
+
+func getCountByFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(countByTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var sumByTemplate = parseMethodTemplate("SumBy", `
+        // SumBy{{.TargetName}} is a method on {{.List}} that takes a function of type {{.Type}} -> {{.TargetType}} and returns the sum of its results for all members of the original list
+        func (l {{.List}}) SumBy{{.TargetName}}(f func({{.Type}}) {{.TargetType}}) {{.TargetType}} {
+            var sum {{.TargetType}}
+            for _, t := range l {
+                sum += f(t)
+            }
+            return sum
+        }
+        `)
+
+func getSumByFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(sumByTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var minByTemplate = parseMethodTemplate("MinBy", `
+        // MinBy{{.TargetName}} is a method on {{.List}} that takes a function of type {{.Type}} -> {{.TargetType}} and returns the first member of the original list for which the function returned the smallest value, or false if the list is empty
+        func (l {{.List}}) MinBy{{.TargetName}}(f func({{.Type}}) {{.TargetType}}) ({{.Type}}, bool) {
+            var result {{.Type}}
+            if len(l) == 0 {
+                return result, false
+            }
+            result, resultKey := l[0], f(l[0])
+            for _, t := range l[1:] {
+                if key := f(t); key < resultKey {
+                    result, resultKey = t, key
+                }
+            }
+            return result, true
+        }
+        `)
+
+func getMinByFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(minByTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var maxByTemplate = parseMethodTemplate("MaxBy", `
+        // MaxBy{{.TargetName}} is a method on {{.List}} that takes a function of type {{.Type}} -> {{.TargetType}} and returns the first member of the original list for which the function returned the largest value, or false if the list is empty
+        func (l {{.List}}) MaxBy{{.TargetName}}(f func({{.Type}}) {{.TargetType}}) ({{.Type}}, bool) {
+            var result {{.Type}}
+            if len(l) == 0 {
+                return result, false
+            }
+            result, resultKey := l[0], f(l[0])
+            for _, t := range l[1:] {
+                if key := f(t); key > resultKey {
+                    result, resultKey = t, key
+                }
+            }
+            return result, true
+        }
+        `)
+
+func getMaxByFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(maxByTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var containsTemplate = parseMethodTemplate("Contains", `
+        // Contains is a method on {{.List}} that takes a value of type {{.Type}} and returns true if the list has a member equal to it
+        func (l {{.List}}) Contains(v {{.Type}}) bool {
+            {{- if .Slices}}
+            return slices.Contains(l, v)
+            {{- else}}
+            for _, t := range l {
+                if t == v {
+                    return true
+                }
+            }
+            return false
+            {{- end}}
+        }
+        `)
+
+func getContainsFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(containsTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var sortTemplate = parseMethodTemplate("Sort", `
+        // Sort is a method on {{.List}} that sorts the members of the list in ascending order, in place, and returns the list
+        func (l {{.List}}) Sort() {{.List}} {
+            {{- if .Slices}}
+            slices.Sort(l)
+            {{- else}}
+            sort.Slice(l, func(i, j int) bool {
+                return l[i] < l[j]
+            })
+            {{- end}}
+            return l
+        }
+        `)
+
+func getSortFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(sortTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var sumTemplate = parseMethodTemplate("Sum", `
+        // Sum is a method on {{.List}} that returns the sum of the members of the list
+        func (l {{.List}}) Sum() {{.Type}} {
+            var sum {{.Type}}
+            for _, t := range l {
+                sum += t
+            }
+            return sum
+        }
+        `)
+
+func getSumFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(sumTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var minTemplate = parseMethodTemplate("Min", `
+        // Min is a method on {{.List}} that returns the smallest member of the list, or false if the list is empty
+        func (l {{.List}}) Min() ({{.Type}}, bool) {
+            var result {{.Type}}
+            if len(l) == 0 {
+                return result, false
+            }
+            {{- if .Slices}}
+            return slices.Min(l), true
+            {{- else}}
+            result = l[0]
+            for _, t := range l[1:] {
+                if t < result {
+                    result = t
+                }
+            }
+            return result, true
+            {{- end}}
+        }
+        `)
+
+func getMinFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(minTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var maxTemplate = parseMethodTemplate("Max", `
+        // Max is a method on {{.List}} that returns the largest member of the list, or false if the list is empty
+        func (l {{.List}}) Max() ({{.Type}}, bool) {
+            var result {{.Type}}
+            if len(l) == 0 {
+                return result, false
+            }
+            {{- if .Slices}}
+            return slices.Max(l), true
+            {{- else}}
+            result = l[0]
+            for _, t := range l[1:] {
+                if t > result {
+                    result = t
+                }
+            }
+            return result, true
+            {{- end}}
+        }
+        `)
+
+func getMaxFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(maxTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+// filterMapTemplate - FilterMap is only generated for the other types: within the same type, Filter suffices
+var filterMapTemplate = parseMethodTemplate("FilterMap", `{{if .TargetName}}
+        // FilterMap{{.TargetName}} is a method on {{.List}} that applies the filter(s) and map to the list members in a single loop and returns the resulting list.
+        func (l {{.List}}) FilterMap{{.TargetName}}(fMap func({{.Type}}) {{.TargetType}}, fFilters ...func({{.Type}}) bool) {{.TargetList}} {
+            l2 := {{.TargetList}}{}
+            for _, t := range l {
+                pass := true
+                for _, f := range fFilters {
+                    if !f(t){
+                        pass = false
+                        break
+                    }
+                }
+                if pass {
+                    l2 = append(l2, fMap(t))
+                }
+            }
+            return l2
+        }
+        {{end}}`)
+
+func getFilterMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(filterMapTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+// pFilterMapTemplate - PFilterMap is only generated for the other types: within the same type, PFilter suffices
+var pFilterMapTemplate = parseMethodTemplate("PFilterMap", `{{if .TargetName}}
+        // PFilterMap{{.TargetName}} is similar to FilterMap{{.TargetName}} except that it executes the method on each member in parallel.
+        func (l {{.List}}) PFilterMap{{.TargetName}}(fMap func({{.Type}}) {{.TargetType}}, fFilters ...func({{.Type}}) bool) {{.TargetList}} {
+            l2 := {{.TargetList}}{}
+            mutex := sync.Mutex{}
+            wg := sync.WaitGroup{}
+            wg.Add(len(l))
+            
+            for _, t := range l {
+                go func(t {{.Type}}){
+                    pass := true
+                    for _, f := range fFilters {
+                        if !f(t) {
+                            pass = false
+                            break
+                        }
+                    }
+                    if pass {
+                        mutex.Lock()
+                        l2 = append(l2, fMap(t))
+                        mutex.Unlock()
+                    }
+                    wg.Done()
+                }(t)
+            }
+            wg.Wait()
+            return l2
+        }
+        {{end}}`)
+
+func getPFilterMapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(pFilterMapTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var mapInPlaceTemplate = parseMethodTemplate("MapInPlace", `
+        // MapInPlace is a method on {{.List}} that takes a function of type {{.Type}} -> {{.Type}} and replaces every member of the list with the result of applying the function to it. It returns the original list, no new list is allocated.
+        func (l {{.List}}) MapInPlace(f func({{.Type}}) {{.Type}}) {{.List}} {
+            for i, t := range l {
+                l[i] = f(t)
+            }
+            return l
+        }
+        `)
+
+func getMapInPlaceFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(mapInPlaceTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var filterInPlaceTemplate = parseMethodTemplate("FilterInPlace", `
+        // FilterInPlace is a method on {{.List}} that takes a function of type {{.Type}} -> bool and moves the members for which the function returned true to the front of the list. It returns the original list re-sliced to these members, no new list is allocated. The members after them are set to the zero value of {{.Type}} so that they can be garbage collected, the original list should not be used anymore.
+        func (l {{.List}}) FilterInPlace(f func({{.Type}}) bool) {{.List}} {
+            n := 0
+            for _, t := range l {
+                if f(t) {
+                    l[n] = t
+                    n++
+                }
+            }
+            {{- if .Slices}}
+            clear(l[n:])
+            {{- else}}
+            var zero {{.Type}}
+            for i := n; i < len(l); i++ {
+                l[i] = zero
+            }
+            {{- end}}
+            return l[:n]
+        }
+        `)
+
+func getFilterInPlaceFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(filterInPlaceTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var compactNilTemplate = parseMethodTemplate("CompactNil", `
+        // CompactNil is a method on {{.List}} that returns a list of type {{.List}} which contains all the members of the original list which are not nil
+        func (l {{.List}}) CompactNil() {{.List}} {
+            l2 := make({{.List}}, 0, len(l))
+            for _, t := range l {
+                if t != nil {
+                    l2 = append(l2, t)
+                }
+            }
+            return l2
+        }
+        `)
+
+func getCompactNilFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(compactNilTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var growTemplate = parseMethodTemplate("Grow", `
+        // Grow is a method on {{.List}} that returns the list with room for at least n more members before it needs to grow, copying it to a new backing array if needed. It panics if n is negative.
+        func (l {{.List}}) Grow(n int) {{.List}} {
+            {{- if .Slices}}
+            return slices.Grow(l, n)
+            {{- else}}
+            if n < 0 {
+                panic("{{.List}}.Grow: cannot be negative")
+            }
+            if cap(l)-len(l) < n {
+                l2 := make({{.List}}, len(l), len(l)+n)
+                copy(l2, l)
+                return l2
+            }
+            return l
+            {{- end}}
+        }
+        `)
+
+func getGrowFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(growTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var clipTemplate = parseMethodTemplate("Clip", `
+        // Clip is a method on {{.List}} that returns the list without its unused capacity, so that appending to the result always copies it to a new backing array
+        func (l {{.List}}) Clip() {{.List}} {
+            {{- if .Slices}}
+            return slices.Clip(l)
+            {{- else}}
+            return l[:len(l):len(l)]
+            {{- end}}
+        }
+        `)
+
+func getClipFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(clipTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var truncateTemplate = parseMethodTemplate("Truncate", `
+        // Truncate is a method on {{.List}} that returns the first n members of the list, keeping its capacity. The members after the first n are set to the zero value of {{.Type}} in the backing array so that they can be garbage collected. If the list contains fewer than n members then the entire list is returned.
+        func (l {{.List}}) Truncate(n int) {{.List}} {
+            if n >= len(l) {
+                return l
+            }
+            {{- if .Slices}}
+            clear(l[n:])
+            {{- else}}
+            var zero {{.Type}}
+            for i := n; i < len(l); i++ {
+                l[i] = zero
+            }
+            {{- end}}
+            return l[:n]
+        }
+        `)
+
+func getTruncateFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(truncateTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var valuesTemplate = parseMethodTemplate("Values", `
+        // Values is a method on {{.List}} that returns an iterator over the members of the list, which can be ranged over or passed to the functions taking an iter.Seq
+        func (l {{.List}}) Values() iter.Seq[{{.Type}}] {
+            return slices.Values(l)
+        }
+        `)
+
+func getValuesFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(valuesTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var backwardTemplate = parseMethodTemplate("Backward", `
+        // Backward is a method on {{.List}} that returns an iterator over the indexes and members of the list, from the last member to the first
+        func (l {{.List}}) Backward() iter.Seq2[int, {{.Type}}] {
+            return slices.Backward(l)
+        }
+        `)
+
+func getBackwardFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(backwardTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var byTemplate = parseMethodTemplate("By", `
+        // {{.List}}By is an adapter for {{.List}} which implements sort.Interface using the less function it was created with
+        type {{.List}}By struct {
+            l    {{.List}}
+            less func({{.Type}}, {{.Type}}) bool
+        }
+
+        // By is a method on {{.List}} that takes a function of type ({{.Type}}, {{.Type}}) -> bool which reports whether its first argument must sort before its second and returns a {{.List}}By which can be passed to sort.Sort or sort.Stable. Sorting the adapter sorts the original list in place.
+        func (l {{.List}}) By(less func({{.Type}}, {{.Type}}) bool) {{.List}}By {
+            return {{.List}}By{l, less}
+        }
+
+        // Len is a method on {{.List}}By that returns the number of members in the list, as required by sort.Interface
+        func (b {{.List}}By) Len() int {
+            return len(b.l)
+        }
+
+        // Less is a method on {{.List}}By that reports whether the member at index i must sort before the member at index j, as required by sort.Interface
+        func (b {{.List}}By) Less(i, j int) bool {
+            return b.less(b.l[i], b.l[j])
+        }
+
+        // Swap is a method on {{.List}}By that swaps the members at indexes i and j, as required by sort.Interface
+        func (b {{.List}}By) Swap(i, j int) {
+            b.l[i], b.l[j] = b.l[j], b.l[i]
+        }
+        `)
+
+func getByFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(byTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var heapTemplate = parseMethodTemplate("Heap", `
+        // {{.List}}Heap is an adapter for {{.List}} which implements heap.Interface using the less function it was created with
+        type {{.List}}Heap struct {
+            l    {{.List}}
+            less func({{.Type}}, {{.Type}}) bool
+        }
+
+        // Heap is a method on {{.List}} that takes a function of type ({{.Type}}, {{.Type}}) -> bool which reports whether its first argument must be popped before its second and returns a {{.List}}Heap which can be used with the container/heap package. The heap reorders the original list in place, heap.Init must be called before the other heap functions.
+        func (l {{.List}}) Heap(less func({{.Type}}, {{.Type}}) bool) *{{.List}}Heap {
+            return &{{.List}}Heap{l, less}
+        }
+
+        // Len is a method on {{.List}}Heap that returns the number of members in the heap, as required by heap.Interface
+        func (h *{{.List}}Heap) Len() int {
+            return len(h.l)
+        }
+
+        // Less is a method on {{.List}}Heap that reports whether the member at index i must be popped before the member at index j, as required by heap.Interface
+        func (h *{{.List}}Heap) Less(i, j int) bool {
+            return h.less(h.l[i], h.l[j])
+        }
+
+        // Swap is a method on {{.List}}Heap that swaps the members at indexes i and j, as required by heap.Interface
+        func (h *{{.List}}Heap) Swap(i, j int) {
+            h.l[i], h.l[j] = h.l[j], h.l[i]
+        }
+
+        // Push is a method on {{.List}}Heap that adds x, which must be of type {{.Type}}, to the end of the list, as required by heap.Interface. Use heap.Push to add members to the heap.
+        func (h *{{.List}}Heap) Push(x {{.Any}}) {
+            h.l = append(h.l, x.({{.Type}}))
+        }
+
+        // Pop is a method on {{.List}}Heap that removes and returns the last member of the list, as required by heap.Interface. Use heap.Pop to remove the first member of the heap.
+        func (h *{{.List}}Heap) Pop() {{.Any}} {
+            n := len(h.l) - 1
+            t := h.l[n]
+            h.l = h.l[:n]
+            return t
+        }
+
+        // List is a method on {{.List}}Heap that returns the members of the heap in their current order
+        func (h *{{.List}}Heap) List() {{.List}} {
+            return h.l
+        }
+        `)
+
+func getHeapFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(heapTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var stringTemplate = parseMethodTemplate("String", `{{if le .StringMax 0}}
+        // String is a method on {{.List}} that returns a readable representation of the list, with the members formatted as by fmt.Print and separated by {{quote .StringSep}}
+        func (l {{.List}}) String() string {
+            var b bytes.Buffer
+            b.WriteString("[")
+            for i, t := range l {
+                if i > 0 {
+                    b.WriteString({{quote .StringSep}})
+                }
+                fmt.Fprint(&b, t)
+            }
+            b.WriteString("]")
+            return b.String()
+        }
+        {{else}}
+        // String is a method on {{.List}} that returns a readable representation of the list, with the members formatted as by fmt.Print and separated by {{quote .StringSep}}. Only the first {{.StringMax}} members are shown, followed by the number of members left out.
+        func (l {{.List}}) String() string {
+            var b bytes.Buffer
+            b.WriteString("[")
+            for i, t := range l {
+                if i > 0 {
+                    b.WriteString({{quote .StringSep}})
+                }
+                if i == {{.StringMax}} {
+                    fmt.Fprintf(&b, "... (%d more)", len(l)-i)
+                    break
+                }
+                fmt.Fprint(&b, t)
+            }
+            b.WriteString("]")
+            return b.String()
+        }
+        {{end}}`)
+
+func getStringFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(stringTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var sqlTemplate = parseMethodTemplate("SQL", `{{if eq .SQLEncoding "json"}}
+        // Value is a method on {{.List}} that implements driver.Valuer by encoding the list as a JSON array. A nil list is stored as NULL.
+        func (l {{.List}}) Value() (driver.Value, error) {
+            if l == nil {
+                return nil, nil
+            }
+            b, err := json.Marshal(l)
+            if err != nil {
+                return nil, err
+            }
+            return b, nil
+        }
+
+        // Scan is a method on {{.List}} that implements sql.Scanner by decoding a JSON array. NULL is scanned as a nil list.
+        func (l *{{.List}}) Scan(src {{.Any}}) error {
+            switch src := src.(type) {
+            case nil:
+                *l = nil
+                return nil
+            case []byte:
+                return json.Unmarshal(src, l)
+            case string:
+                return json.Unmarshal([]byte(src), l)
+            }
+            return fmt.Errorf("cannot scan %T into {{.List}}", src)
+        }
+        {{else}}
+        // Value is a method on {{.List}} that implements driver.Valuer by encoding the list as a Postgres array literal. Members which encode as JSON numbers or booleans are written as their JSON encoding, members which encode as JSON strings are quoted and the other members, such as structs, are quoted as their JSON encoding. A nil list is stored as NULL.
+        func (l {{.List}}) Value() (driver.Value, error) {
+            if l == nil {
+                return nil, nil
+            }
+            b := []byte{'{'}
+            for i, t := range l {
+                if i > 0 {
+                    b = append(b, ',')
+                }
+                e, err := json.Marshal(t)
+                if err != nil {
+                    return nil, err
+                }
+                var s string
+                switch {
+                case string(e) == "null":
+                    b = append(b, "NULL"...)
+                case string(e) == "true" || string(e) == "false" || e[0] == '-' || e[0] >= '0' && e[0] <= '9':
+                    b = append(b, e...)
+                default:
+                    if json.Unmarshal(e, &s) != nil {
+                        s = string(e)
+                    }
+                    b = append(b, '"')
+                    for _, c := range []byte(s) {
+                        if c == '"' || c == '\\' {
+                            b = append(b, '\\')
+                        }
+                        b = append(b, c)
+                    }
+                    b = append(b, '"')
+                }
+            }
+            return string(append(b, '}')), nil
+        }
+
+        // Scan is a method on {{.List}} that implements sql.Scanner by decoding a one-dimensional Postgres array literal. Unquoted members are decoded as JSON if possible and as strings otherwise, and quoted members as strings if possible and as JSON otherwise. NULL members are scanned as the zero value and a NULL array as a nil list.
+        func (l *{{.List}}) Scan(src {{.Any}}) error {
+            var b []byte
+            switch src := src.(type) {
+            case nil:
+                *l = nil
+                return nil
+            case []byte:
+                b = src
+            case string:
+                b = []byte(src)
+            default:
+                return fmt.Errorf("cannot scan %T into {{.List}}", src)
+            }
+            if len(b) < 2 || b[0] != '{' || b[len(b)-1] != '}' {
+                return fmt.Errorf("cannot scan %q into {{.List}}: not an array literal", b)
+            }
+            l2 := {{.List}}{}
+            for b = b[1 : len(b)-1]; len(b) > 0; {
+                var e []byte
+                quoted := b[0] == '"'
+                if quoted {
+                    i := 1
+                    for ; i < len(b) && b[i] != '"'; i++ {
+                        if b[i] == '\\' {
+                            i++
+                        }
+                        if i < len(b) {
+                            e = append(e, b[i])
+                        }
+                    }
+                    if i >= len(b) {
+                        return fmt.Errorf("cannot scan into {{.List}}: unterminated quoted member")
+                    }
+                    b = b[i+1:]
+                } else {
+                    i := 0
+                    for i < len(b) && b[i] != ',' {
+                        i++
+                    }
+                    e, b = b[:i], b[i:]
+                }
+                if len(b) > 0 {
+                    if b[0] != ',' {
+                        return fmt.Errorf("cannot scan into {{.List}}: unexpected %q after member", b[0])
+                    }
+                    b = b[1:]
+                }
+                var t {{.Type}}
+                switch {
+                case !quoted && string(e) == "NULL":
+                case !quoted && json.Unmarshal(e, &t) == nil:
+                default:
+                    q, _ := json.Marshal(string(e))
+                    if err := json.Unmarshal(q, &t); err != nil && (!quoted || json.Unmarshal(e, &t) != nil) {
+                        return fmt.Errorf("cannot scan %q into {{.List}}: %s", e, err)
+                    }
+                }
+                l2 = append(l2, t)
+            }
+            *l = l2
+            return nil
+        }
+        {{end}}`)
+
+func getSQLFunction(listName, typeName, targetType, targetTypeName string) string {
+	if *sqlEncoding != "json" && *sqlEncoding != "postgres" {
+		fatalf("-sql-encoding parameter '%s' is not valid", *sqlEncoding)
+	}
+	return executeMethodTemplate(sqlTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var jsonTemplate = parseMethodTemplate("JSON", `
+        // ToJSON is a method on {{.List}} that returns the JSON encoding of the list
+        func (l {{.List}}) ToJSON() ([]byte, error) {
+            return json.Marshal(l)
+        }
+
+        // {{funcName "From" .List "JSON"}} decodes the JSON array b into a {{.List}}
+        func {{funcName "From" .List "JSON"}}(b []byte) ({{.List}}, error) {
+            var l {{.List}}
+            err := json.Unmarshal(b, &l)
+            return l, err
+        }
+        {{if .JSONNilEmpty}}
+        // MarshalJSON is a method on {{.List}} that implements json.Marshaler, encoding a nil list as [] instead of null
+        func (l {{.List}}) MarshalJSON() ([]byte, error) {
+            if l == nil {
+                return []byte("[]"), nil
+            }
+            return json.Marshal([]{{.Type}}(l))
+        }
+        {{end}}`)
+
+func getJSONFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(jsonTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var builderTemplate = parseMethodTemplate("Builder", `
+        // {{.List}}Builder is used to construct a {{.List}} member by member
+        type {{.List}}Builder struct {
+            l {{.List}}
+        }
+
+        // {{constructorName (print .List "Builder")}} returns a {{.List}}Builder with room for capacity members before the list needs to grow
+        func {{constructorName (print .List "Builder")}}(capacity int) *{{.List}}Builder {
+            return &{{.List}}Builder{make({{.List}}, 0, capacity)}
+        }
+
+        // Add is a method on {{.List}}Builder that adds t to the end of the list and returns the builder
+        func (b *{{.List}}Builder) Add(t {{.Type}}) *{{.List}}Builder {
+            b.l = append(b.l, t)
+            return b
+        }
+
+        // AddIf is a method on {{.List}}Builder that adds t to the end of the list if cond is true and returns the builder
+        func (b *{{.List}}Builder) AddIf(cond bool, t {{.Type}}) *{{.List}}Builder {
+            if cond {
+                b.l = append(b.l, t)
+            }
+            return b
+        }
+
+        // AddAll is a method on {{.List}}Builder that adds all the given members to the end of the list and returns the builder
+        func (b *{{.List}}Builder) AddAll(t ...{{.Type}}) *{{.List}}Builder {
+            b.l = append(b.l, t...)
+            return b
+        }
+
+        // Build is a method on {{.List}}Builder that returns the list built so far. Members added to the builder afterwards do not affect the returned list.
+        func (b *{{.List}}Builder) Build() {{.List}} {
+            return b.l[:len(b.l):len(b.l)]
+        }
+        `)
+
+func getBuilderFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(builderTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var safeListTemplate = parseMethodTemplate("SafeList", `
+        // {{wrapperName .List "SafeList"}} is a wrapper around {{.List}} which embeds a mutex so that the list can be shared safely between goroutines. The zero value is an empty list ready to use.
+        type {{wrapperName .List "SafeList"}} struct {
+            sync.RWMutex
+            l {{.List}}
+        }
+
+        // Append is a method on {{wrapperName .List "SafeList"}} that adds the given members to the end of the list while holding the lock.
+        func (s *{{wrapperName .List "SafeList"}}) Append(t ...{{.Type}}) {
+            s.Lock()
+            s.l = append(s.l, t...)
+            s.Unlock()
+        }
+
+        // Snapshot is a method on {{wrapperName .List "SafeList"}} that returns a copy of the underlying {{.List}} which can be used without holding the lock.
+        func (s *{{wrapperName .List "SafeList"}}) Snapshot() {{.List}} {
+            s.RLock()
+            defer s.RUnlock()
+            l2 := make({{.List}}, len(s.l))
+            copy(l2, s.l)
+            return l2
+        }
+
+        // Filter is a method on {{wrapperName .List "SafeList"}} that takes a function of type {{.Type}} -> bool and returns a list of type {{.List}} which contains all members of the list for which the function returned true. The read lock is held while the function is applied.
+        func (s *{{wrapperName .List "SafeList"}}) Filter(f func({{.Type}}) bool) {{.List}} {
+            s.RLock()
+            defer s.RUnlock()
+            l2 := {{.List}}{}
+            for _, t := range s.l {
+                if f(t) {
+                    l2 = append(l2, t)
+                }
+            }
+            return l2
+        }
+
+        // Each is a method on {{wrapperName .List "SafeList"}} that takes a function of type {{.Type}} -> void and applies the function to each member of the list while holding the read lock.
+        func (s *{{wrapperName .List "SafeList"}}) Each(f func({{.Type}})) *{{wrapperName .List "SafeList"}} {
+            s.RLock()
+            defer s.RUnlock()
+            for _, t := range s.l {
+                f(t)
+            }
+            return s
+        }
+        `)
+
+func getSafeListFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(safeListTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var vectorTemplate = parseMethodTemplate("Vector", `
+        // {{wrapperName .List "Vector"}} is an immutable list that holds members of type {{.Type}}. None of its methods modify the vector they are called on and the vectors they return never alias a backing array that can be modified.
+        type {{wrapperName .List "Vector"}} struct {
+            l {{.List}}
+        }
+
+        // {{constructorName (wrapperName .List "Vector")}} returns a {{wrapperName .List "Vector"}} which holds a copy of the given members.
+        func {{constructorName (wrapperName .List "Vector")}}(t ...{{.Type}}) {{wrapperName .List "Vector"}} {
+            l := make({{.List}}, len(t))
+            copy(l, t)
+            return {{wrapperName .List "Vector"}}{l}
+        }
+
+        // Len is a method on {{wrapperName .List "Vector"}} that returns the number of members in the vector.
+        func (v {{wrapperName .List "Vector"}}) Len() int {
+            return len(v.l)
+        }
+
+        // At is a method on {{wrapperName .List "Vector"}} that returns the member at index i. It panics if i is out of range.
+        func (v {{wrapperName .List "Vector"}}) At(i int) {{.Type}} {
+            return v.l[i]
+        }
+
+        // Append is a method on {{wrapperName .List "Vector"}} that returns a new vector containing the members of the original vector followed by the given members. The original vector is left untouched.
+        func (v {{wrapperName .List "Vector"}}) Append(t ...{{.Type}}) {{wrapperName .List "Vector"}} {
+            l2 := make({{.List}}, len(v.l), len(v.l)+len(t))
+            copy(l2, v.l)
+            return {{wrapperName .List "Vector"}}{append(l2, t...)}
+        }
+
+        // Map is a method on {{wrapperName .List "Vector"}} that takes a function of type {{.Type}} -> {{.Type}} and returns a new vector containing the result of applying the function to every member of the vector.
+        func (v {{wrapperName .List "Vector"}}) Map(f func({{.Type}}) {{.Type}}) {{wrapperName .List "Vector"}} {
+            l2 := make({{.List}}, len(v.l))
+            for i, t := range v.l {
+                l2[i] = f(t)
+            }
+            return {{wrapperName .List "Vector"}}{l2}
+        }
+
+        // Filter is a method on {{wrapperName .List "Vector"}} that takes a function of type {{.Type}} -> bool and returns a new vector containing the members for which the function returned true.
+        func (v {{wrapperName .List "Vector"}}) Filter(f func({{.Type}}) bool) {{wrapperName .List "Vector"}} {
+            l2 := {{.List}}{}
+            for _, t := range v.l {
+                if f(t) {
+                    l2 = append(l2, t)
+                }
+            }
+            return {{wrapperName .List "Vector"}}{l2}
+        }
+
+        // Take is a method on {{wrapperName .List "Vector"}} that returns a vector of the first n members. The result shares its members with the original vector which is safe since neither of them can be modified.
+        func (v {{wrapperName .List "Vector"}}) Take(n int) {{wrapperName .List "Vector"}} {
+            if len(v.l) >= n {
+                return {{wrapperName .List "Vector"}}{v.l[:n:n]}
+            }
+            return v
+        }
+
+        // Drop is a method on {{wrapperName .List "Vector"}} that returns a vector of all but the first n members. The result shares its members with the original vector which is safe since neither of them can be modified.
+        func (v {{wrapperName .List "Vector"}}) Drop(n int) {{wrapperName .List "Vector"}} {
+            if len(v.l) >= n {
+                return {{wrapperName .List "Vector"}}{v.l[n:]}
+            }
+            return {{wrapperName .List "Vector"}}{}
+        }
+
+        // ToList is a method on {{wrapperName .List "Vector"}} that returns the members of the vector as a {{.List}}. The list is a copy and can be modified freely.
+        func (v {{wrapperName .List "Vector"}}) ToList() {{.List}} {
+            l2 := make({{.List}}, len(v.l))
+            copy(l2, v.l)
+            return l2
+        }
+        `)
+
+func getVectorFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(vectorTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+var pipelineTemplate = parseMethodTemplate("Pipeline", `
+        // {{wrapperName .List "Pipeline"}} is a concurrent pipeline processing the members of a {{.List}} through stages which run in their own goroutines, connected by channels. Each stage runs with a bounded number of workers and the channels between the stages are bounded, so that a slow stage holds back the stages before it. The order of the members is kept.
+        type {{wrapperName .List "Pipeline"}} struct {
+            src     {{.List}}
+            // stages returns the member replacing a member, whether it is kept, and an error stopping the pipeline
+            stages   []func(context.Context, {{.Type}}) ({{.Type}}, bool, error)
+            contexts []context.Context
+            workers  int
+            buffer   int
+        }
+
+        // {{constructorName (wrapperName .List "Pipeline")}} returns a {{wrapperName .List "Pipeline"}} processing the members of src, with one worker per stage and unbuffered channels.
+        func {{constructorName (wrapperName .List "Pipeline")}}(src {{.List}}) *{{wrapperName .List "Pipeline"}} {
+            return &{{wrapperName .List "Pipeline"}}{src: src, workers: 1}
+        }
+
+        // Map is a method on {{wrapperName .List "Pipeline"}} that adds a stage replacing each member with the result of f, and returns the pipeline
+        func (p *{{wrapperName .List "Pipeline"}}) Map(f func({{.Type}}) {{.Type}}) *{{wrapperName .List "Pipeline"}} {
+            p.stages = append(p.stages, func(_ context.Context, t {{.Type}}) ({{.Type}}, bool, error) {
+                return f(t), true, nil
+            })
+            p.contexts = append(p.contexts, nil)
+            return p
+        }
+
+        // MapCtx is a method on {{wrapperName .List "Pipeline"}} that adds a stage replacing each member with the result of f, which is given the context of the run, and returns the pipeline. An error of f, or the error of ctx once it is done, stops the pipeline.
+        func (p *{{wrapperName .List "Pipeline"}}) MapCtx(ctx context.Context, f func(context.Context, {{.Type}}) ({{.Type}}, error)) *{{wrapperName .List "Pipeline"}} {
+            p.stages = append(p.stages, func(ctx context.Context, t {{.Type}}) ({{.Type}}, bool, error) {
+                t, err := f(ctx, t)
+                return t, err == nil, err
+            })
+            p.contexts = append(p.contexts, ctx)
+            return p
+        }
+
+        // Filter is a method on {{wrapperName .List "Pipeline"}} that adds a stage keeping the members for which f returns true, and returns the pipeline
+        func (p *{{wrapperName .List "Pipeline"}}) Filter(f func({{.Type}}) bool) *{{wrapperName .List "Pipeline"}} {
+            p.stages = append(p.stages, func(_ context.Context, t {{.Type}}) ({{.Type}}, bool, error) {
+                return t, f(t), nil
+            })
+            p.contexts = append(p.contexts, nil)
+            return p
+        }
+
+        // FilterCtx is a method on {{wrapperName .List "Pipeline"}} that adds a stage keeping the members for which f, which is given the context of the run, returns true, and returns the pipeline. An error of f, or the error of ctx once it is done, stops the pipeline.
+        func (p *{{wrapperName .List "Pipeline"}}) FilterCtx(ctx context.Context, f func(context.Context, {{.Type}}) (bool, error)) *{{wrapperName .List "Pipeline"}} {
+            p.stages = append(p.stages, func(ctx context.Context, t {{.Type}}) ({{.Type}}, bool, error) {
+                keep, err := f(ctx, t)
+                return t, keep && err == nil, err
+            })
+            p.contexts = append(p.contexts, ctx)
+            return p
+        }
+
+        // Workers is a method on {{wrapperName .List "Pipeline"}} that sets the number of goroutines running each stage, at least 1, and returns the pipeline
+        func (p *{{wrapperName .List "Pipeline"}}) Workers(n int) *{{wrapperName .List "Pipeline"}} {
+            if n < 1 {
+                n = 1
+            }
+            p.workers = n
+            return p
+        }
+
+        // Buffer is a method on {{wrapperName .List "Pipeline"}} that sets the number of members each channel between the stages can hold before its stage waits for the next one, and returns the pipeline
+        func (p *{{wrapperName .List "Pipeline"}}) Buffer(n int) *{{wrapperName .List "Pipeline"}} {
+            if n < 0 {
+                n = 0
+            }
+            p.buffer = n
+            return p
+        }
+
+        // Run is a method on {{wrapperName .List "Pipeline"}} that runs the pipeline like RunCtx with a context which is never done
+        func (p *{{wrapperName .List "Pipeline"}}) Run() ({{.List}}, error) {
+            return p.RunCtx(context.Background())
+        }
+
+        // RunCtx is a method on {{wrapperName .List "Pipeline"}} that runs the stages of the pipeline on its members and returns the list of the members which went through all of them, in their original order. The pipeline is stopped at the first error of a stage, which is returned, or when ctx is done, in which case its error is returned.
+        func (p *{{wrapperName .List "Pipeline"}}) RunCtx(ctx context.Context) ({{.List}}, error) {
+            ctx, cancel := context.WithCancel(ctx)
+            defer cancel()
+
+            type item struct {
+                i int
+                t {{.Type}}
+            }
+            var once sync.Once
+            var stageErr error
+            fail := func(err error) {
+                once.Do(func() {
+                    stageErr = err
+                    cancel()
+                })
+            }
+
+            in := make(chan item, p.buffer)
+            go func(out chan<- item) {
+                defer close(out)
+                for i, t := range p.src {
+                    select {
+                    case out <- item{i, t}:
+                    case <-ctx.Done():
+                        return
+                    }
+                }
+            }(in)
+
+            for s, stage := range p.stages {
+                stageCtx := p.contexts[s]
+                if stageCtx != nil {
+                    // stop the pipeline once the context of the stage is done, even if f is waiting on the context of the run
+                    go func() {
+                        select {
+                        case <-stageCtx.Done():
+                            fail(stageCtx.Err())
+                        case <-ctx.Done():
+                        }
+                    }()
+                }
+                out := make(chan item, p.buffer)
+                var wg sync.WaitGroup
+                wg.Add(p.workers)
+                for w := 0; w < p.workers; w++ {
+                    go func(in <-chan item, stage func(context.Context, {{.Type}}) ({{.Type}}, bool, error)) {
+                        defer wg.Done()
+                        for it := range in {
+                            if ctx.Err() != nil {
+                                return
+                            }
+                            if stageCtx != nil && stageCtx.Err() != nil {
+                                fail(stageCtx.Err())
+                                return
+                            }
+                            t, keep, err := stage(ctx, it.t)
+                            if err != nil {
+                                fail(err)
+                                return
+                            }
+                            if !keep {
+                                continue
+                            }
+                            select {
+                            case out <- item{it.i, t}:
+                            case <-ctx.Done():
+                                return
+                            }
+                        }
+                    }(in, stage)
+                }
+                go func() {
+                    wg.Wait()
+                    close(out)
+                }()
+                in = out
+            }
+
+            results := make({{.List}}, len(p.src))
+            kept := make([]bool, len(p.src))
+            for it := range in {
+                results[it.i] = it.t
+                kept[it.i] = true
+            }
+            // a stage may still be failing after the last member went through, and once.Do returns after it did
+            once.Do(func() {})
+            if stageErr != nil {
+                return nil, stageErr
+            }
+            if err := ctx.Err(); err != nil {
+                return nil, err
+            }
+            l2 := make({{.List}}, 0, len(p.src))
+            for i, t := range results {
+                if kept[i] {
+                    l2 = append(l2, t)
+                }
+            }
+            return l2, nil
+        }
+        `)
+
+func getPipelineFunction(listName, typeName, targetType, targetTypeName string) string {
+	return executeMethodTemplate(pipelineTemplate, listName, typeName, targetType, targetTypeName)
+}
+
+// getWrapperName - get the name of a type which wraps the list type listName
+func getWrapperName(listName, suffix string) string {
+	return strings.TrimSuffix(listName, *listSuffix) + suffix
+}
+
+// getConstructorName - get the name of the constructor function for typeName
+func getConstructorName(typeName string) string {
+	return getFuncName("New", typeName, "")
+}
+
+// getFuncName - get the name of a function for typeName made of a verb, the type name and a suffix. The function is exported only if the type is
+func getFuncName(verb, typeName, suffix string) string {
+	if isExportedName(typeName) {
+		return verb + typeName + suffix
+	}
+	return strings.ToLower(verb[:1]) + verb[1:] + getExportedName(typeName) + suffix
+}
@@ -1,12 +1,20 @@
-// Package main - generated by fungen; DO NOT EDIT
-package main
+// Package fungen - generated by fungen; DO NOT EDIT
+//
+// Generated by fungen devel with:
+//
+//	fungen -methods Filter,Each -types Generator
+//
+// To generate it again, run the command above from its directory or add this directive to a file of the package:
+//
+//	//go:generate fungen -methods Filter,Each -types Generator
+package fungen
 
 // GeneratorList is the type for a list that holds members of type Generator
 type GeneratorList []Generator
 
 // Filter is a method on GeneratorList that takes a function of type Generator -> bool returns a list of type GeneratorList which contains all members from the original list for which the function returned true
 func (l GeneratorList) Filter(f func(Generator) bool) GeneratorList {
-	l2 := []Generator{}
+	l2 := make(GeneratorList, 0, len(l))
 	for _, t := range l {
 		if f(t) {
 			l2 = append(l2, t)
@@ -0,0 +1,3289 @@
+package fungen
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestFilterGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getFilterFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // Filter is a method on %[1]s that takes a function of type %[2]s -> bool returns a list of type %[1]s which contains all members from the original list for which the function returned true
+        func (l %[1]s) Filter(f func(%[2]s) bool) %[1]s {
+            l2 := make(%[1]s, 0, len(l))
+            for _, t := range l {
+                if f(t) {
+                    l2 = append(l2, t)
+                }
+            }
+            return l2
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestPFilterGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getPFilterFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // PFilter is similar to the Filter method except that the filter is applied to all the elements in parallel. The order of resulting elements cannot be guaranteed. 
+        func (l %[1]s) PFilter(f func(%[2]s) bool) %[1]s {
+            wg := sync.WaitGroup{}
+            mutex := sync.Mutex{}
+            l2 := make(%[1]s, 0, len(l))
+            for _, t := range l {
+                wg.Add(1)
+                go func(t %[2]s){
+                    if f(t) {
+                        mutex.Lock()
+                        l2 = append(l2, t)
+                        mutex.Unlock()
+                    }            
+                    wg.Done()
+                }(t)
+            }
+            wg.Wait()
+            return l2
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestEachGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getEachFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // Each is a method on %[1]s that takes a function of type %[2]s -> void and applies the function to each member of the list and then returns the original list.
+        func (l %[1]s) Each(f func(%[2]s)) %[1]s {
+            for _, t := range l {
+                f(t) 
+            }
+            return l
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestEachIGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getEachIFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // EachI is a method on %[1]s that takes a function of type (int, %[2]s) -> void and applies the function to each member of the list and then returns the original list. The int parameter to the function is the index of the element.
+        func (l %[1]s) EachI(f func(int, %[2]s)) %[1]s {
+            for i, t := range l {
+                f(i, t) 
+            }
+            return l
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestDropWhileGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getDropWhileFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // DropWhile is a method on %[1]s that takes a function of type %[2]s -> bool and returns a list of type %[1]s which excludes the first members from the original list for which the function returned true
+        func (l %[1]s) DropWhile(f func(%[2]s) bool) %[1]s {
+            for i, t := range l {
+                if !f(t) {
+                    return l[i:]
+                }
+            }
+            var l2 %[1]s
+            return l2
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestTakeWhileGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getTakeWhileFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // TakeWhile is a method on %[1]s that takes a function of type %[2]s -> bool and returns a list of type %[1]s which includes only the first members from the original list for which the function returned true
+        func (l %[1]s) TakeWhile(f func(%[2]s) bool) %[1]s {
+            for i, t := range l {
+                if !f(t) {
+                    return l[:i]
+                }
+            }
+            return l
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestTakeGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getTakeFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // Take is a method on %[1]s that takes an integer n and returns the first n elements of the original list. If the list contains fewer than n elements then the entire list is returned.
+        func (l %[1]s) Take(n int) %[1]s {
+            if len(l) >= n {
+                return l[:n]
+            }
+            return l
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestDropGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getDropFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // Drop is a method on %[1]s that takes an integer n and returns all but the first n elements of the original list. If the list contains fewer than n elements then an empty list is returned.
+        func (l %[1]s) Drop(n int) %[1]s {
+            if len(l) >= n {
+                return l[n:]
+            }
+            var l2 %[1]s
+            return l2
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestReduceGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getReduceFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // Reduce is a method on %[1]s that takes a function of type (%[2]s, %[2]s) -> %[2]s and returns a %[2]s which is the result of applying the function to all members of the original list starting from the first member
+        func (l %[1]s) Reduce(t1 %[2]s, f func(%[2]s, %[2]s) %[2]s) %[2]s {
+            for _, t := range l {
+                t1 = f(t1, t)
+            }
+            return t1
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestReduceRightGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getReduceRightFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // ReduceRight is a method on %[1]s that takes a function of type (%[2]s, %[2]s) -> %[2]s and returns a %[2]s which is the result of applying the function to all members of the original list starting from the last member
+        func (l %[1]s) ReduceRight(t1 %[2]s, f func(%[2]s, %[2]s) %[2]s) %[2]s {
+            for i := len(l) - 1; i >= 0; i-- {
+                t := l[i]
+                t1 = f(t, t1)
+            }
+            return t1
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestMapGeneration1(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "stringList", "string", "string", ""
+	result := f(getMapFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // Map is a method on stringList that takes a function of type string -> string and applies it to every member of stringList
+        func (l stringList) Map(f func(string) string) stringList {
+            l2 := make(stringList, len(l))
+            for i, t := range l {
+                l2[i] = f(t)
+            }
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestMapGeneration2(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "stringList", "string", "int", "int"
+	result := f(getMapFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // MapInt is a method on stringList that takes a function of type string -> int and applies it to every member of stringList
+        func (l stringList) MapInt(f func(string) int) intList {
+            l2 := make(intList, len(l))
+            for i, t := range l {
+                l2[i] = f(t)
+            }
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestMapGeneration3(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "stringList", "string", "int", "I"
+	result := f(getMapFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // MapI is a method on stringList that takes a function of type string -> int and applies it to every member of stringList
+        func (l stringList) MapI(f func(string) int) IList {
+            l2 := make(IList, len(l))
+            for i, t := range l {
+                l2[i] = f(t)
+            }
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestPMapGeneration1(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "stringList", "string", "string", ""
+	result := f(getPMapFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // PMap is similar to Map except that it executes the function on each member in parallel.
+        func (l stringList) PMap(f func(string) string) stringList {
+            wg := sync.WaitGroup{}
+            l2 := make(stringList, len(l))
+            for i, t := range l {
+                wg.Add(1)
+                go func(i int, t string) {
+			l2[i] = f(t)
+			wg.Done()
+		}(i, t)
+            }
+            wg.Wait()
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestPMapGeneration2(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "stringList", "string", "int", "int"
+	result := f(getPMapFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // PMapInt is similar to MapInt except that it executes the function on each member in parallel.
+        func (l stringList) PMapInt(f func(string) int) intList {
+            wg := sync.WaitGroup{}
+            l2 := make(intList, len(l))
+            for i, t := range l {
+                wg.Add(1)
+                go func(i int, t string) {
+			l2[i] = f(t)
+			wg.Done()
+		}(i, t)
+            }
+            wg.Wait()
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestPMapGeneration3(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "stringList", "string", "int", "I"
+	result := f(getPMapFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // PMapI is similar to MapI except that it executes the function on each member in parallel.
+        func (l stringList) PMapI(f func(string) int) IList {
+            wg := sync.WaitGroup{}
+            l2 := make(IList, len(l))
+            for i, t := range l {
+                wg.Add(1)
+                go func(i int, t string) {
+			l2[i] = f(t)
+			wg.Done()
+		}(i, t)
+            }
+            wg.Wait()
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestAllGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getAllFunction(listName, typeName, "", ""))
+
+	expectedRaw := `
+        // All is a method on stringList that returns true if all the members of the list satisfy a function or if the list is empty.
+        func (l stringList) All(f func(string) bool) bool {
+            for _, t := range l {
+                if !f(t) {
+                    return false
+                }
+            }
+            return true
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestAnyGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getAnyFunction(listName, typeName, "", ""))
+
+	expectedRaw := `
+        // Any is a method on stringList that returns true if at least one member of the list satisfies a function. It returns false if the list is empty.
+        func (l stringList) Any(f func(string) bool) bool {
+            for _, t := range l {
+                if f(t) {
+                    return true
+                }
+            }
+            return false
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestFilterMapGeneration(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "stringList", "string", "int", "int"
+	result := f(getFilterMapFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // FilterMapInt is a method on stringList that applies the filter(s) and map to the list members in a single loop and returns the resulting list.
+        func (l stringList) FilterMapInt(fMap func(string) int, fFilters ...func(string) bool) intList {
+            l2 := intList{}
+            for _, t := range l {
+                pass := true
+                for _, f := range fFilters {
+                    if !f(t){
+                        pass = false
+                        break
+                    }
+                }
+                if pass {
+                    l2 = append(l2, fMap(t))
+                }
+            }
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestPFilterMapGeneration(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "stringList", "string", "int", "int"
+	result := f(getPFilterMapFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // PFilterMapInt is similar to FilterMapInt except that it executes the method on each member in parallel.
+        func (l stringList) PFilterMapInt(fMap func(string) int, fFilters ...func(string) bool) intList {
+            l2 := intList{}
+            mutex := sync.Mutex{}
+            wg := sync.WaitGroup{}
+            wg.Add(len(l))
+            
+            for _, t := range l {
+                go func(t string){
+                    pass := true
+                    for _, f := range fFilters {
+                        if !f(t) {
+                            pass = false
+                            break
+                        }
+                    }
+                    if pass {
+                        mutex.Lock()
+                        l2 = append(l2, fMap(t))
+                        mutex.Unlock()
+                    }
+                    wg.Done()
+                }(t)
+            }
+            wg.Wait()
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestSafeListGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getSafeListFunction(listName, typeName, "", ""))
+
+	expectedRaw := `
+        // stringSafeList is a wrapper around stringList which embeds a mutex so that the list can be shared safely between goroutines. The zero value is an empty list ready to use.
+        type stringSafeList struct {
+            sync.RWMutex
+            l stringList
+        }
+
+        // Append is a method on stringSafeList that adds the given members to the end of the list while holding the lock.
+        func (s *stringSafeList) Append(t ...string) {
+            s.Lock()
+            s.l = append(s.l, t...)
+            s.Unlock()
+        }
+
+        // Snapshot is a method on stringSafeList that returns a copy of the underlying stringList which can be used without holding the lock.
+        func (s *stringSafeList) Snapshot() stringList {
+            s.RLock()
+            defer s.RUnlock()
+            l2 := make(stringList, len(s.l))
+            copy(l2, s.l)
+            return l2
+        }
+
+        // Filter is a method on stringSafeList that takes a function of type string -> bool and returns a list of type stringList which contains all members of the list for which the function returned true. The read lock is held while the function is applied.
+        func (s *stringSafeList) Filter(f func(string) bool) stringList {
+            s.RLock()
+            defer s.RUnlock()
+            l2 := stringList{}
+            for _, t := range s.l {
+                if f(t) {
+                    l2 = append(l2, t)
+                }
+            }
+            return l2
+        }
+
+        // Each is a method on stringSafeList that takes a function of type string -> void and applies the function to each member of the list while holding the read lock.
+        func (s *stringSafeList) Each(f func(string)) *stringSafeList {
+            s.RLock()
+            defer s.RUnlock()
+            for _, t := range s.l {
+                f(t)
+            }
+            return s
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestVectorGeneration(t *testing.T) {
+	result := f(getVectorFunction("stringList", "string", "", ""))
+
+	expectedRaw := `
+        // stringVector is an immutable list that holds members of type string. None of its methods modify the vector they are called on and the vectors they return never alias a backing array that can be modified.
+        type stringVector struct {
+            l stringList
+        }
+
+        // newStringVector returns a stringVector which holds a copy of the given members.
+        func newStringVector(t ...string) stringVector {
+            l := make(stringList, len(t))
+            copy(l, t)
+            return stringVector{l}
+        }
+
+        // Len is a method on stringVector that returns the number of members in the vector.
+        func (v stringVector) Len() int {
+            return len(v.l)
+        }
+
+        // At is a method on stringVector that returns the member at index i. It panics if i is out of range.
+        func (v stringVector) At(i int) string {
+            return v.l[i]
+        }
+
+        // Append is a method on stringVector that returns a new vector containing the members of the original vector followed by the given members. The original vector is left untouched.
+        func (v stringVector) Append(t ...string) stringVector {
+            l2 := make(stringList, len(v.l), len(v.l)+len(t))
+            copy(l2, v.l)
+            return stringVector{append(l2, t...)}
+        }
+
+        // Map is a method on stringVector that takes a function of type string -> string and returns a new vector containing the result of applying the function to every member of the vector.
+        func (v stringVector) Map(f func(string) string) stringVector {
+            l2 := make(stringList, len(v.l))
+            for i, t := range v.l {
+                l2[i] = f(t)
+            }
+            return stringVector{l2}
+        }
+
+        // Filter is a method on stringVector that takes a function of type string -> bool and returns a new vector containing the members for which the function returned true.
+        func (v stringVector) Filter(f func(string) bool) stringVector {
+            l2 := stringList{}
+            for _, t := range v.l {
+                if f(t) {
+                    l2 = append(l2, t)
+                }
+            }
+            return stringVector{l2}
+        }
+
+        // Take is a method on stringVector that returns a vector of the first n members. The result shares its members with the original vector which is safe since neither of them can be modified.
+        func (v stringVector) Take(n int) stringVector {
+            if len(v.l) >= n {
+                return stringVector{v.l[:n:n]}
+            }
+            return v
+        }
+
+        // Drop is a method on stringVector that returns a vector of all but the first n members. The result shares its members with the original vector which is safe since neither of them can be modified.
+        func (v stringVector) Drop(n int) stringVector {
+            if len(v.l) >= n {
+                return stringVector{v.l[n:]}
+            }
+            return stringVector{}
+        }
+
+        // ToList is a method on stringVector that returns the members of the vector as a stringList. The list is a copy and can be modified freely.
+        func (v stringVector) ToList() stringList {
+            l2 := make(stringList, len(v.l))
+            copy(l2, v.l)
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestPipelineGeneration(t *testing.T) {
+	result := f(getPipelineFunction("stringList", "string", "", ""))
+
+	for _, decl := range []string{
+		"type stringPipeline struct {",
+		"func newStringPipeline(src stringList) *stringPipeline {",
+		"func (p *stringPipeline) FilterCtx(ctx context.Context, f func(context.Context, string) (bool, error)) *stringPipeline {",
+		"func (p *stringPipeline) Workers(n int) *stringPipeline {",
+		"func (p *stringPipeline) RunCtx(ctx context.Context) (stringList, error) {",
+	} {
+		if !strings.Contains(result, decl) {
+			t.Errorf("missing %q", decl)
+		}
+	}
+}
+
+func TestGetPairs(t *testing.T) {
+	result := getPairs("int,string;string,CustomType")
+
+	if len(result) != 2 || result[0] != [2]string{"int", "string"} || result[1] != [2]string{"string", "CustomType"} {
+		t.Fail()
+	}
+}
+
+func TestPairGeneration(t *testing.T) {
+	result := f(generatePair("int", "string", map[string]string{"string": "Str"}, map[string]bool{"Take": true}))
+
+	expectedRaw := `
+        // intStrPair is a pair of values of type int and string
+        type intStrPair struct {
+            First  int
+            Second string
+        }
+
+        // newIntStrPair returns a intStrPair holding the given values
+        func newIntStrPair(first int, second string) intStrPair {
+            return intStrPair{first, second}
+        }
+
+        // Values is a method on intStrPair that returns both members of the pair
+        func (p intStrPair) Values() (int, string) {
+            return p.First, p.Second
+        }
+
+        // WithFirst is a method on intStrPair that returns a copy of the pair with the first member replaced by the given value
+        func (p intStrPair) WithFirst(first int) intStrPair {
+            p.First = first
+            return p
+        }
+
+        // WithSecond is a method on intStrPair that returns a copy of the pair with the second member replaced by the given value
+        func (p intStrPair) WithSecond(second string) intStrPair {
+            p.Second = second
+            return p
+        }
+
+        // intStrPairList is the type for a list that holds members of type intStrPair
+        type intStrPairList []intStrPair
+
+        // Take is a method on intStrPairList that takes an integer n and returns the first n elements of the original list. If the list contains fewer than n elements then the entire list is returned.
+        func (l intStrPairList) Take(n int) intStrPairList {
+            if len(l) >= n {
+                return l[:n]
+            }
+            return l
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestPairMethods(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the go command is not installed")
+	}
+	resetState()
+	defer resetState()
+	pairNames = map[[2]string]string{{"int", "string"}: "intStringPair", {"string", "int"}: "stringIntPair", {"int", "int"}: "intIntPair"}
+	m := getTypeMap("int,string")
+	if code := generate("string", "stringList", m, map[string]bool{"Map": true}); strings.Contains(code, "Enumerate") || strings.Contains(code, "ZipPairs") {
+		t.Fatal(code)
+	}
+	dir, _ := ioutil.TempDir("", "fungen")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(dir+"/main.go", []byte(f(`package main
+
+import "fmt"
+
+func main() {
+	fmt.Println(stringList{"a", "b", "c"}.Enumerate())
+	fmt.Println(intList{1, 2}.ZipPairsString(stringList{"a", "b", "c"}))
+	fmt.Println(stringList{"a", "a", "b", "a"}.RunLengthEncode(), intList{}.RunLengthEncode())
+}
+`+generate("int", "intList", m, map[string]bool{"ZipPairs": true, "RunLengthEncode": true})+
+		generate("string", "stringList", m, map[string]bool{"Enumerate": true, "RunLengthEncode": true})+`
+type intStringPair struct {
+	First  int
+	Second string
+}
+
+type intStringPairList []intStringPair
+
+type stringIntPair struct {
+	First  string
+	Second int
+}
+
+type stringIntPairList []stringIntPair
+
+type intIntPair struct {
+	First, Second int
+}
+
+type intIntPairList []intIntPair
+`)), 0644)
+
+	cmd := exec.Command("go", "run", "main.go")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil || string(output) != "[{0 a} {1 b} {2 c}]\n[{1 a} {2 b}]\n[{a 2} {b 1} {a 1}] []\n" {
+		t.Fatal(string(output), err)
+	}
+}
+
+func TestByGeneration(t *testing.T) {
+	result := f(getByFunction("stringList", "string", "", ""))
+
+	expectedRaw := `
+        // stringListBy is an adapter for stringList which implements sort.Interface using the less function it was created with
+        type stringListBy struct {
+            l    stringList
+            less func(string, string) bool
+        }
+
+        // By is a method on stringList that takes a function of type (string, string) -> bool which reports whether its first argument must sort before its second and returns a stringListBy which can be passed to sort.Sort or sort.Stable. Sorting the adapter sorts the original list in place.
+        func (l stringList) By(less func(string, string) bool) stringListBy {
+            return stringListBy{l, less}
+        }
+
+        // Len is a method on stringListBy that returns the number of members in the list, as required by sort.Interface
+        func (b stringListBy) Len() int {
+            return len(b.l)
+        }
+
+        // Less is a method on stringListBy that reports whether the member at index i must sort before the member at index j, as required by sort.Interface
+        func (b stringListBy) Less(i, j int) bool {
+            return b.less(b.l[i], b.l[j])
+        }
+
+        // Swap is a method on stringListBy that swaps the members at indexes i and j, as required by sort.Interface
+        func (b stringListBy) Swap(i, j int) {
+            b.l[i], b.l[j] = b.l[j], b.l[i]
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestHeapGeneration(t *testing.T) {
+	result := f(getHeapFunction("stringList", "string", "", ""))
+
+	expectedRaw := `
+        // stringListHeap is an adapter for stringList which implements heap.Interface using the less function it was created with
+        type stringListHeap struct {
+            l    stringList
+            less func(string, string) bool
+        }
+
+        // Heap is a method on stringList that takes a function of type (string, string) -> bool which reports whether its first argument must be popped before its second and returns a stringListHeap which can be used with the container/heap package. The heap reorders the original list in place, heap.Init must be called before the other heap functions.
+        func (l stringList) Heap(less func(string, string) bool) *stringListHeap {
+            return &stringListHeap{l, less}
+        }
+
+        // Len is a method on stringListHeap that returns the number of members in the heap, as required by heap.Interface
+        func (h *stringListHeap) Len() int {
+            return len(h.l)
+        }
+
+        // Less is a method on stringListHeap that reports whether the member at index i must be popped before the member at index j, as required by heap.Interface
+        func (h *stringListHeap) Less(i, j int) bool {
+            return h.less(h.l[i], h.l[j])
+        }
+
+        // Swap is a method on stringListHeap that swaps the members at indexes i and j, as required by heap.Interface
+        func (h *stringListHeap) Swap(i, j int) {
+            h.l[i], h.l[j] = h.l[j], h.l[i]
+        }
+
+        // Push is a method on stringListHeap that adds x, which must be of type string, to the end of the list, as required by heap.Interface. Use heap.Push to add members to the heap.
+        func (h *stringListHeap) Push(x interface{}) {
+            h.l = append(h.l, x.(string))
+        }
+
+        // Pop is a method on stringListHeap that removes and returns the last member of the list, as required by heap.Interface. Use heap.Pop to remove the first member of the heap.
+        func (h *stringListHeap) Pop() interface{} {
+            n := len(h.l) - 1
+            t := h.l[n]
+            h.l = h.l[:n]
+            return t
+        }
+
+        // List is a method on stringListHeap that returns the members of the heap in their current order
+        func (h *stringListHeap) List() stringList {
+            return h.l
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestStringGeneration(t *testing.T) {
+	result := f(getStringFunction("intList", "int", "", ""))
+
+	expectedRaw := `
+        // String is a method on intList that returns a readable representation of the list, with the members formatted as by fmt.Print and separated by ", "
+        func (l intList) String() string {
+            var b bytes.Buffer
+            b.WriteString("[")
+            for i, t := range l {
+                if i > 0 {
+                    b.WriteString(", ")
+                }
+                fmt.Fprint(&b, t)
+            }
+            b.WriteString("]")
+            return b.String()
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+	if code := generate("func()", "handlerList", map[string]string{"func()": "handler"}, map[string]bool{"String": true}); strings.Contains(code, "String()") {
+		t.Fatal(code)
+	}
+}
+
+func TestStringGenerationTruncated(t *testing.T) {
+	*stringSep, *stringMax = "; ", 10
+	defer func() { *stringSep, *stringMax = ", ", 0 }()
+	result := f(getStringFunction("intList", "int", "", ""))
+
+	expectedRaw := `
+        // String is a method on intList that returns a readable representation of the list, with the members formatted as by fmt.Print and separated by "; ". Only the first 10 members are shown, followed by the number of members left out.
+        func (l intList) String() string {
+            var b bytes.Buffer
+            b.WriteString("[")
+            for i, t := range l {
+                if i > 0 {
+                    b.WriteString("; ")
+                }
+                if i == 10 {
+                    fmt.Fprintf(&b, "... (%d more)", len(l)-i)
+                    break
+                }
+                fmt.Fprint(&b, t)
+            }
+            b.WriteString("]")
+            return b.String()
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestGetImports(t *testing.T) {
+	result := getImports(map[string]bool{"PMap": true, "String": true, "Map": true}, []string{"int"})
+
+	if result != "import (\n\"bytes\"\n\"fmt\"\n\"sync\"\n)" {
+		t.Fail()
+	}
+}
+
+func TestSQLGeneration(t *testing.T) {
+	result := f(getSQLFunction("intList", "int", "", ""))
+
+	expectedRaw := `
+        // Value is a method on intList that implements driver.Valuer by encoding the list as a JSON array. A nil list is stored as NULL.
+        func (l intList) Value() (driver.Value, error) {
+            if l == nil {
+                return nil, nil
+            }
+            b, err := json.Marshal(l)
+            if err != nil {
+                return nil, err
+            }
+            return b, nil
+        }
+
+        // Scan is a method on intList that implements sql.Scanner by decoding a JSON array. NULL is scanned as a nil list.
+        func (l *intList) Scan(src interface{}) error {
+            switch src := src.(type) {
+            case nil:
+                *l = nil
+                return nil
+            case []byte:
+                return json.Unmarshal(src, l)
+            case string:
+                return json.Unmarshal([]byte(src), l)
+            }
+            return fmt.Errorf("cannot scan %T into intList", src)
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestPostgresRoundTrip(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the go command is not installed")
+	}
+	*sqlEncoding = "postgres"
+	defer func() { *sqlEncoding = "json" }()
+	dir, _ := ioutil.TempDir("", "fungen")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(dir+"/main.go", []byte(f(`package main
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+type point struct {
+	Name string
+	X, Y int
+}
+
+type pointList []point
+
+func main() {
+	l := pointList{{"a, \\\"b\\\" {c}", 1, -2}, {}}
+	value, err := l.Value()
+	if err != nil {
+		panic(err)
+	}
+	var got pointList
+	if err := got.Scan(value); err != nil {
+		panic(err)
+	}
+	fmt.Print(reflect.DeepEqual(got, l))
+}
+`+getSQLFunction("pointList", "point", "", ""))), 0644)
+
+	cmd := exec.Command("go", "run", "main.go")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil || string(output) != "true" {
+		t.Fatal(string(output), err)
+	}
+}
+
+func TestJSONGeneration(t *testing.T) {
+	*jsonEmpty = true
+	defer func() { *jsonEmpty = false }()
+	result := f(getJSONFunction("stringList", "string", "", ""))
+
+	expectedRaw := `
+        // ToJSON is a method on stringList that returns the JSON encoding of the list
+        func (l stringList) ToJSON() ([]byte, error) {
+            return json.Marshal(l)
+        }
+
+        // fromStringListJSON decodes the JSON array b into a stringList
+        func fromStringListJSON(b []byte) (stringList, error) {
+            var l stringList
+            err := json.Unmarshal(b, &l)
+            return l, err
+        }
+
+        // MarshalJSON is a method on stringList that implements json.Marshaler, encoding a nil list as [] instead of null
+        func (l stringList) MarshalJSON() ([]byte, error) {
+            if l == nil {
+                return []byte("[]"), nil
+            }
+            return json.Marshal([]string(l))
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestGetFuncsSource(t *testing.T) {
+	src := "package main\n" + getMapFunction("intList", "int", "string", "string") + getTakeFunction("intList", "int", "", "")
+	result := getFuncsSource(src, map[string]string{"intList": "int", "stringList": "string"})
+
+	expectedRaw := `package main
+
+        // MapStringIntList is a function on []int that takes a function of type int -> string and applies it to every member of []int
+        func MapStringIntList(l []int, f func(int) string) []string {
+            l2 := make([]string, len(l))
+            for i, t := range l {
+                l2[i] = f(t)
+            }
+            return l2
+        }
+
+        // TakeIntList is a function on []int that takes an integer n and returns the first n elements of the original list. If the list contains fewer than n elements then the entire list is returned.
+        func TakeIntList(l []int, n int) []int {
+            if len(l) >= n {
+                return l[:n]
+            }
+            return l
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestGetInterfacesSource(t *testing.T) {
+	src := "package main\n" + getTakeFunction("intList", "int", "", "") + getAllFunction("intList", "int", "", "")
+	result := getInterfacesSource(src, map[string]string{"intList": "int"})
+
+	expectedRaw := "package main\n" + getTakeFunction("intList", "int", "", "") + getAllFunction("intList", "int", "", "") + `
+        // IntLister is the interface describing the methods generated for intList
+        type IntLister interface {
+            Take(n int) intList
+            All(f func(int) bool) bool
+        }
+
+        // intList must satisfy IntLister
+        var _ IntLister = intList(nil)
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestBuilderGeneration(t *testing.T) {
+	result := f(getBuilderFunction("stringList", "string", "", ""))
+
+	expectedRaw := `
+        // stringListBuilder is used to construct a stringList member by member
+        type stringListBuilder struct {
+            l stringList
+        }
+
+        // newStringListBuilder returns a stringListBuilder with room for capacity members before the list needs to grow
+        func newStringListBuilder(capacity int) *stringListBuilder {
+            return &stringListBuilder{make(stringList, 0, capacity)}
+        }
+
+        // Add is a method on stringListBuilder that adds t to the end of the list and returns the builder
+        func (b *stringListBuilder) Add(t string) *stringListBuilder {
+            b.l = append(b.l, t)
+            return b
+        }
+
+        // AddIf is a method on stringListBuilder that adds t to the end of the list if cond is true and returns the builder
+        func (b *stringListBuilder) AddIf(cond bool, t string) *stringListBuilder {
+            if cond {
+                b.l = append(b.l, t)
+            }
+            return b
+        }
+
+        // AddAll is a method on stringListBuilder that adds all the given members to the end of the list and returns the builder
+        func (b *stringListBuilder) AddAll(t ...string) *stringListBuilder {
+            b.l = append(b.l, t...)
+            return b
+        }
+
+        // Build is a method on stringListBuilder that returns the list built so far. Members added to the builder afterwards do not affect the returned list.
+        func (b *stringListBuilder) Build() stringList {
+            return b.l[:len(b.l):len(b.l)]
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestGrowGeneration(t *testing.T) {
+	result := f(getGrowFunction("intList", "int", "", ""))
+
+	expectedRaw := `
+        // Grow is a method on intList that returns the list with room for at least n more members before it needs to grow, copying it to a new backing array if needed. It panics if n is negative.
+        func (l intList) Grow(n int) intList {
+            if n < 0 {
+                panic("intList.Grow: cannot be negative")
+            }
+            if cap(l)-len(l) < n {
+                l2 := make(intList, len(l), len(l)+n)
+                copy(l2, l)
+                return l2
+            }
+            return l
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestClipGeneration(t *testing.T) {
+	result := f(getClipFunction("intList", "int", "", ""))
+
+	expectedRaw := `
+        // Clip is a method on intList that returns the list without its unused capacity, so that appending to the result always copies it to a new backing array
+        func (l intList) Clip() intList {
+            return l[:len(l):len(l)]
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestTruncateGeneration(t *testing.T) {
+	result := f(getTruncateFunction("intList", "int", "", ""))
+
+	expectedRaw := `
+        // Truncate is a method on intList that returns the first n members of the list, keeping its capacity. The members after the first n are set to the zero value of int in the backing array so that they can be garbage collected. If the list contains fewer than n members then the entire list is returned.
+        func (l intList) Truncate(n int) intList {
+            if n >= len(l) {
+                return l
+            }
+            var zero int
+            for i := n; i < len(l); i++ {
+                l[i] = zero
+            }
+            return l[:n]
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestCompactNilGeneration(t *testing.T) {
+	result := f(getCompactNilFunction("UserPList", "*User", "", ""))
+
+	expectedRaw := `
+        // CompactNil is a method on UserPList that returns a list of type UserPList which contains all the members of the original list which are not nil
+        func (l UserPList) CompactNil() UserPList {
+            l2 := make(UserPList, 0, len(l))
+            for _, t := range l {
+                if t != nil {
+                    l2 = append(l2, t)
+                }
+            }
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestGetImportsForTypes(t *testing.T) {
+	*importPaths = "uuid=github.com/satori/go.uuid,errgroup=golang.org/x/sync/errgroup"
+	defer func() { *importPaths = "" }()
+	result := getImports(map[string]bool{}, []string{"int", "*time.Time", "uuid.UUID", "errgroup.Group"})
+
+	if result != "import (\n\"golang.org/x/sync/errgroup\"\n\"time\"\nuuid \"github.com/satori/go.uuid\"\n)" {
+		t.Fail()
+	}
+}
+
+func TestGetTypeName(t *testing.T) {
+	m := getTypeMap("*time.Time,uuid.UUID:ID,*User:UserP")
+
+	if getTypeName("*time.Time", m) != "Time" || getTypeName("uuid.UUID", m) != "ID" || getTypeName("*User", m) != "UserP" || getTypeName("int", m) != "int" {
+		t.Fail()
+	}
+}
+
+func TestGetTypeMapComposite(t *testing.T) {
+	m := getTypeMap("[]byte,map[string]int:SI,'func(a, b int) bool':Pred,chan int")
+
+	if len(m) != 4 || m["[]byte"] != "[]byte" || m["map[string]int"] != "SI" || m["func(a, b int) bool"] != "Pred" || m["chan int"] != "chan int" {
+		t.Fail()
+	}
+	if getTypeName("[]byte", m) != "byteSlice" || getTypeName("map[string]int", m) != "SI" || getTypeName("chan int", m) != "intChan" {
+		t.Fail()
+	}
+	if getTypeName("map[string]*time.Time", m) != "stringTimeMap" {
+		t.Fail()
+	}
+}
+
+func TestGetTypeNameFunc(t *testing.T) {
+	m := getTypeMap("'func(Handler) Handler':Middleware")
+
+	if getTypeName("func(Handler) Handler", m) != "Middleware" || getTypeName("func(Request) Response", m) != "RequestResponseFunc" || getTypeName("func(a, b int) bool", m) != "intIntBoolFunc" || getTypeName("func()", m) != "func" {
+		t.Fail()
+	}
+	if !isNilableType("func()", "") || !isNilableType("[]int", "") || isNilableType("int", "") || isEncodableType("func()", "") || !isEncodableType("[]int", "") {
+		t.Fail()
+	}
+}
+
+func TestGetTypeNameArray(t *testing.T) {
+	m := getTypeMap("[16]byte,[4]float64:Vec4,[Size]int")
+
+	if getTypeName("[16]byte", m) != "byteArray16" || getTypeName("[4]float64", m) != "Vec4" || getTypeName("[Size]int", m) != "intArraySize" || getTypeName("[][2]int", m) != "intArray2Slice" {
+		t.Fail()
+	}
+}
+
+func TestGetPointerReceiversSource(t *testing.T) {
+	src := "package main\n" + getTakeFunction("intList", "int", "", "") + getSQLFunction("intList", "int", "", "")
+	result := getPointerReceiversSource(src, map[string]string{"intList": "int"})
+
+	expectedRaw := `package main
+
+        // Take is a method on intList that takes an integer n and returns the first n elements of the original list. If the list contains fewer than n elements then the entire list is returned.
+        func (l *intList) Take(n int) intList {
+            if len(*l) >= n {
+                return (*l)[:n]
+            }
+            return *l
+        }
+
+        // Value is a method on intList that implements driver.Valuer by encoding the list as a JSON array. A nil list is stored as NULL.
+        func (l *intList) Value() (driver.Value, error) {
+            if *l == nil {
+                return nil, nil
+            }
+            b, err := json.Marshal(*l)
+            if err != nil {
+                return nil, err
+            }
+            return b, nil
+        }
+        ` + getSQLFunction("intList", "int", "", "")[strings.Index(getSQLFunction("intList", "int", "", ""), "// Scan"):]
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestGetUnexportedSource(t *testing.T) {
+	src := "package main\n" + getMapFunction("intList", "int", "int", "") + getStringFunction("intList", "int", "", "") + getEachIFunction("intList", "int", "", "")
+	result := getUnexportedSource(src, map[string]string{"intList": "int"})
+
+	expectedRaw := "package main\n" + `
+        // mapList is a method on intList that takes a function of type int -> int and applies it to every member of intList
+        func (l intList) mapList(f func(int) int) intList {
+            l2 := make(intList, len(l))
+            for i, t := range l {
+                l2[i] = f(t)
+            }
+            return l2
+        }
+        ` + getStringFunction("intList", "int", "", "") + `
+        // eachI is a method on intList that takes a function of type (int, int) -> void and applies the function to each member of the list and then returns the original list. The int parameter to the function is the index of the element.
+        func (l intList) eachI(f func(int, int)) intList {
+            for i, t := range l {
+                f(i, t)
+            }
+            return l
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestGetListNameWithSuffix(t *testing.T) {
+	*listSuffix = "Slice"
+	defer func() { *listSuffix = "List" }()
+	m := getTypeMap("int,string:Str")
+
+	if getListName("int", m) != "intSlice" || getListName("string", m) != "StrSlice" || getWrapperName("intSlice", "Vector") != "intVector" {
+		t.Fail()
+	}
+
+	result := f(getMapFunction("intSlice", "int", "string", "Str"))
+	expectedRaw := `
+        // MapStr is a method on intSlice that takes a function of type int -> string and applies it to every member of intSlice
+        func (l intSlice) MapStr(f func(int) string) StrSlice {
+            l2 := make(StrSlice, len(l))
+            for i, t := range l {
+                l2[i] = f(t)
+            }
+            return l2
+        }
+        `
+
+	if result != f(expectedRaw) {
+		t.Fail()
+	}
+}
+
+func TestGetTypeMapListNames(t *testing.T) {
+	defer func() { listNames = map[string]string{} }()
+	m := getTypeMap("User::Users,string:Str:Strings,int")
+
+	if m["User"] != "User" || m["string"] != "Str" || getListName("User", m) != "Users" || getListName("string", m) != "Strings" || getListName("int", m) != "intList" {
+		t.Fail()
+	}
+
+	result := f(getMapFunction("intList", "int", "User", "User"))
+	expectedRaw := `
+        // MapUser is a method on intList that takes a function of type int -> User and applies it to every member of intList
+        func (l intList) MapUser(f func(int) User) Users {
+            l2 := make(Users, len(l))
+            for i, t := range l {
+                l2[i] = f(t)
+            }
+            return l2
+        }
+        `
+
+	if result != f(expectedRaw) {
+		t.Fail()
+	}
+}
+
+func TestGetReceiversSource(t *testing.T) {
+	*receiver = "auto"
+	defer func() { *receiver = "l" }()
+	src := "package main\n" + getTakeFunction("intList", "int", "", "")
+	result := getReceiversSource(src, map[string]string{"intList": "int"})
+
+	expectedRaw := `package main
+
+        // Take is a method on intList that takes an integer n and returns the first n elements of the original list. If the list contains fewer than n elements then the entire list is returned.
+        func (il intList) Take(n int) intList {
+            if len(il) >= n {
+                return il[:n]
+            }
+            return il
+        }
+        `
+
+	if result != f(expectedRaw) || getReceiverName("byteSliceList") != "bsl" {
+		t.Fail()
+	}
+}
+
+func TestMapInPlaceGeneration(t *testing.T) {
+	result := f(getMapInPlaceFunction("intList", "int", "", ""))
+
+	expectedRaw := `
+        // MapInPlace is a method on intList that takes a function of type int -> int and replaces every member of the list with the result of applying the function to it. It returns the original list, no new list is allocated.
+        func (l intList) MapInPlace(f func(int) int) intList {
+            for i, t := range l {
+                l[i] = f(t)
+            }
+            return l
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestFilterInPlaceGeneration(t *testing.T) {
+	result := f(getFilterInPlaceFunction("intList", "int", "", ""))
+
+	expectedRaw := `
+        // FilterInPlace is a method on intList that takes a function of type int -> bool and moves the members for which the function returned true to the front of the list. It returns the original list re-sliced to these members, no new list is allocated. The members after them are set to the zero value of int so that they can be garbage collected, the original list should not be used anymore.
+        func (l intList) FilterInPlace(f func(int) bool) intList {
+            n := 0
+            for _, t := range l {
+                if f(t) {
+                    l[n] = t
+                    n++
+                }
+            }
+            var zero int
+            for i := n; i < len(l); i++ {
+                l[i] = zero
+            }
+            return l[:n]
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestTakeGenerationCopy(t *testing.T) {
+	*copyResults = true
+	defer func() { *copyResults = false }()
+	result := f(getTakeFunction("intList", "int", "", ""))
+
+	expectedRaw := `
+        // Take is a method on intList that takes an integer n and returns the first n elements of the original list. If the list contains fewer than n elements then the entire list is returned. The result is a copy which does not share its backing array with the original list.
+        func (l intList) Take(n int) intList {
+            if len(l) >= n {
+                l = l[:n]
+            }
+            l2 := make(intList, len(l))
+            copy(l2, l)
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestDropWhileGenerationCopy(t *testing.T) {
+	*copyResults = true
+	defer func() { *copyResults = false }()
+	result := f(getDropWhileFunction("intList", "int", "", ""))
+
+	expectedRaw := `
+        // DropWhile is a method on intList that takes a function of type int -> bool and returns a list of type intList which excludes the first members from the original list for which the function returned true. The result is a copy which does not share its backing array with the original list.
+        func (l intList) DropWhile(f func(int) bool) intList {
+            for i, t := range l {
+                if !f(t) {
+                    l2 := make(intList, len(l)-i)
+                    copy(l2, l[i:])
+                    return l2
+                }
+            }
+            var l2 intList
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestTakeGenerationFullSlice(t *testing.T) {
+	*fullSlice = true
+	defer func() { *fullSlice = false }()
+	result := f(getTakeFunction("intList", "int", "", ""))
+
+	expectedRaw := `
+        // Take is a method on intList that takes an integer n and returns the first n elements of the original list. If the list contains fewer than n elements then the entire list is returned.
+        func (l intList) Take(n int) intList {
+            if len(l) >= n {
+                return l[:n:n]
+            }
+            return l
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestGenerateNoCrossMaps(t *testing.T) {
+	*noCrossMaps = true
+	defer func() { *noCrossMaps = false }()
+	m := map[string]string{"int": "int", "string": "string"}
+	result := generate("int", "intList", m, map[string]bool{"Map": true, "FilterMap": true})
+
+	if !strings.Contains(result, ") Map(") || strings.Contains(result, "MapString") {
+		t.Fail()
+	}
+}
+
+func TestLogLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	defer logger.SetOutput(os.Stderr)
+
+	*verbose = true
+	generate("User", "UserList", map[string]string{"User": "User"}, map[string]bool{"Map": true, "Sum": true})
+	*verbose = false
+	if !strings.Contains(buf.String(), "fungen: generated UserList.Map\n") || !strings.Contains(buf.String(), "skipped UserList.Sum") {
+		t.Fail()
+	}
+
+	buf.Reset()
+	*quiet = true
+	warnf("skipping %s", "UserList")
+	verbosef("generated %s", "UserList")
+	*quiet = false
+	if buf.Len() != 0 {
+		t.Fail()
+	}
+	warnf("skipping %s", "UserList")
+	if buf.String() != "fungen: Warning: skipping UserList\n" {
+		t.Fail()
+	}
+}
+
+func TestGenerateMapTargets(t *testing.T) {
+	*mapTargets = "int>S"
+	defer func() { *mapTargets = "" }()
+	m := map[string]string{"int": "int", "string": "S", "bool": "bool"}
+	result := generate("int", "intList", m, map[string]bool{"Map": true})
+
+	if !strings.Contains(result, ") Map(") || !strings.Contains(result, ") MapS(") || strings.Contains(result, "MapBool") {
+		t.Fail()
+	}
+
+	result = generate("string", "SList", m, map[string]bool{"Map": true})
+	if !strings.Contains(result, ") Map(") || strings.Contains(result, "MapInt") {
+		t.Fail()
+	}
+}
+
+func TestFlatMapGeneration(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "orderList", "order", "item", "item"
+	result := f(getFlatMapFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // FlatMapItem is a method on orderList that takes a function of type order -> itemList and returns the concatenation of the lists returned by the function for every member of orderList
+        func (l orderList) FlatMapItem(f func(order) itemList) itemList {
+            l2 := itemList{}
+            for _, t := range l {
+                l2 = append(l2, f(t)...)
+            }
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestFilterMapToGeneration(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "stringList", "string", "int", "int"
+	result := f(getFilterMapToFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // FilterMapToInt is a method on stringList that takes a function of type string -> (int, bool) and returns a list of type intList which contains the results of the function for all members of the original list for which it returned true, in a single loop
+        func (l stringList) FilterMapToInt(f func(string) (int, bool)) intList {
+            l2 := intList{}
+            for _, t := range l {
+                if u, ok := f(t); ok {
+                    l2 = append(l2, u)
+                }
+            }
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected || getFilterMapToFunction(listName, typeName, typeName, "") != "" {
+		t.Fail()
+	}
+}
+
+func TestGroupByGeneration(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "userList", "user", "string", "string"
+	result := f(getGroupByFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // GroupByString is a method on userList that takes a function of type user -> string and returns a map from the results of the function to the lists of the members of userList for which it returned them, in their original order
+        func (l userList) GroupByString(f func(user) string) map[string]userList {
+            groups := map[string]userList{}
+            for _, t := range l {
+                key := f(t)
+                groups[key] = append(groups[key], t)
+            }
+            return groups
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestIsComparableType(t *testing.T) {
+	if !isComparableType("", "int") || !isComparableType("", "*User") || !isComparableType("", "[2]string") || !isComparableType("", "struct{ a int; b string }") {
+		t.Fail()
+	}
+	if isComparableType("", "[]int") || isComparableType("", "map[string]int") || isComparableType("", "func()") || isComparableType("", "[2][]int") || isComparableType("", "struct{ a []int }") {
+		t.Fail()
+	}
+}
+
+func TestZipGeneration(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "userList", "user", "int", "int"
+	result := f(getZipFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // ZipInt is a method on userList that takes a list of type intList and a function of type (user, int) -> user and returns a list of type userList which contains the results of the function for the members of both lists at the same index. The result is as long as the shorter list
+        func (l userList) ZipInt(other intList, f func(user, int) user) userList {
+            n := len(l)
+            if len(other) < n {
+                n = len(other)
+            }
+            l2 := make(userList, n)
+            for i := range l2 {
+                l2[i] = f(l[i], other[i])
+            }
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestFoldGeneration(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "userList", "user", "string", "string"
+	result := f(getFoldFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // FoldString is a method on userList that takes a seed of type string and a function of type (string, user) -> string and returns a string which is the result of applying the function to the seed and all members of the original list starting from the first member
+        func (l userList) FoldString(seed string, f func(string, user) string) string {
+            for _, t := range l {
+                seed = f(seed, t)
+            }
+            return seed
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected || getFoldFunction(listName, typeName, typeName, "") != "" {
+		t.Fail()
+	}
+}
+
+func TestSumByGeneration(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "orderList", "order", "float64", "float64"
+	result := f(getSumByFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // SumByFloat64 is a method on orderList that takes a function of type order -> float64 and returns the sum of its results for all members of the original list
+        func (l orderList) SumByFloat64(f func(order) float64) float64 {
+            var sum float64
+            for _, t := range l {
+                sum += f(t)
+            }
+            return sum
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected || isNumericType("", "string") || !isNumericType("", "float64") {
+		t.Fail()
+	}
+}
+
+func TestMinByGeneration(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "userList", "user", "int", "int"
+	result := f(getMinByFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // MinByInt is a method on userList that takes a function of type user -> int and returns the first member of the original list for which the function returned the smallest value, or false if the list is empty
+        func (l userList) MinByInt(f func(user) int) (user, bool) {
+            var result user
+            if len(l) == 0 {
+                return result, false
+            }
+            result, resultKey := l[0], f(l[0])
+            for _, t := range l[1:] {
+                if key := f(t); key < resultKey {
+                    result, resultKey = t, key
+                }
+            }
+            return result, true
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestCountByGeneration(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "orderList", "order", "CustomerID", "CustomerID"
+	result := f(getCountByFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // CountByCustomerID is a method on orderList that takes a function of type order -> CustomerID and returns a map from the results of the function to the number of members of orderList for which it returned them
+        func (l orderList) CountByCustomerID(f func(order) CustomerID) map[CustomerID]int {
+            counts := map[CustomerID]int{}
+            for _, t := range l {
+                counts[f(t)]++
+            }
+            return counts
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestMapNotNilGeneration(t *testing.T) {
+	listName, typeName, targetType, targetTypeName := "stringList", "string", "User", "User"
+	result := f(getMapNotNilFunction(listName, typeName, targetType, targetTypeName))
+
+	expectedRaw := `
+        // MapNotNilUser is a method on stringList that takes a function of type string -> *User and returns a list of type UserList which contains the values pointed to by the results of the function which are not nil
+        func (l stringList) MapNotNilUser(f func(string) *User) UserList {
+            l2 := UserList{}
+            for _, t := range l {
+                if u := f(t); u != nil {
+                    l2 = append(l2, *u)
+                }
+            }
+            return l2
+        }
+        `
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestContainsGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getContainsFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // Contains is a method on %[1]s that takes a value of type %[2]s and returns true if the list has a member equal to it
+        func (l %[1]s) Contains(v %[2]s) bool {
+            for _, t := range l {
+                if t == v {
+                    return true
+                }
+            }
+            return false
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestSortGeneration(t *testing.T) {
+	listName, typeName := "stringList", "string"
+	result := f(getSortFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // Sort is a method on %[1]s that sorts the members of the list in ascending order, in place, and returns the list
+        func (l %[1]s) Sort() %[1]s {
+            sort.Slice(l, func(i, j int) bool {
+                return l[i] < l[j]
+            })
+            return l
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestSumGeneration(t *testing.T) {
+	listName, typeName := "intList", "int"
+	result := f(getSumFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // Sum is a method on %[1]s that returns the sum of the members of the list
+        func (l %[1]s) Sum() %[2]s {
+            var sum %[2]s
+            for _, t := range l {
+                sum += t
+            }
+            return sum
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestMaxGeneration(t *testing.T) {
+	listName, typeName := "intList", "int"
+	result := f(getMaxFunction(listName, typeName, "", ""))
+
+	expectedRaw := fmt.Sprintf(`
+        // Max is a method on %[1]s that returns the largest member of the list, or false if the list is empty
+        func (l %[1]s) Max() (%[2]s, bool) {
+            var result %[2]s
+            if len(l) == 0 {
+                return result, false
+            }
+            result = l[0]
+            for _, t := range l[1:] {
+                if t > result {
+                    result = t
+                }
+            }
+            return result, true
+        }
+        `, listName, typeName)
+
+	expected := f(expectedRaw)
+
+	if result != expected {
+		t.Fail()
+	}
+}
+
+func TestGetTraits(t *testing.T) {
+	defer func() { typeTraits = map[string]map[string]bool{} }()
+	m := getTypeMap("Celsius:C+numeric,UserID")
+	addTraitsOption("UserID+comparable", m)
+
+	if !isNumericType("Celsius", "") || !isOrderedType("Celsius", "") || !isComparableType("", "UserID") || isOrderedType("UserID", "") {
+		t.Fail()
+	}
+	if !isNumericType("int", "") || !isOrderedType("string", "") || isNumericType("string", "") || isOrderedType("bool", "") || isNumericType("complex128", "") {
+		t.Fail()
+	}
+	if !isComparableType("[2]Celsius", "") || isComparableType("[]Celsius", "") || isComparableType("Undeclared", "") {
+		t.Fail()
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	file, err := ioutil.TempFile("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`{
+        "package": "models",
+        "methods": ["Map", "Filter"],
+        "types": [{"type": "int", "name": "I", "methods": ["Sum"], "traits": ["numeric"]}, {"type": "map[string]int", "list": "Counts"}],
+        "options": {"unexported": true}
+    }`)
+	file.Close()
+
+	defer func() {
+		*packageName, *methods, *types, *unexported = "main", "", "", false
+		typeMethods, typeTraits = map[string]string{}, map[string]map[string]bool{}
+	}()
+	loadConfig(file.Name())
+
+	if *packageName != "models" || *methods != "Map,Filter" || !*unexported || typeMethods["int"] != "Sum" || !typeTraits["int"]["numeric"] {
+		t.Fail()
+	}
+	m := getTypeMap(*types)
+	defer func() { listNames = map[string]string{} }()
+	if m["int"] != "I" || getListName("map[string]int", m) != "Counts" {
+		t.Fail()
+	}
+}
+
+func TestLoadDirectives(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(dir+"/types.go", []byte(`package models
+
+// User is a user
+//fungen:methods=Map,Filter alias=U
+type User struct{}
+
+type (
+	//fungen:list=Temps traits=numeric
+	Celsius float64
+
+	ignored int
+)
+`), 0644)
+
+	defer func() { typeMethods, typeTraits = map[string]string{}, map[string]map[string]bool{} }()
+	result := loadDirectives(dir)
+
+	if result != "User:U:,Celsius::Temps" || typeMethods["User"] != "Map,Filter" || !typeTraits["Celsius"]["numeric"] {
+		t.Fail()
+	}
+}
+
+func TestDiscoverLists(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(dir+"/types.go", []byte(`package models
+
+type Users []User
+type Matrix [][2]float64
+type Alias = []int
+type Fixed [3]int
+type Set[T comparable] []T
+`), 0644)
+
+	defer func() { existLists = map[string]bool{} }()
+	result := discoverLists(dir)
+
+	if strings.Join(result, ",") != "'User'::Users,'[2]float64'::Matrix" || !existLists["Users"] || !existLists["Matrix"] {
+		t.Fail()
+	}
+	if generate("User", "Users", map[string]string{"User": "User"}, map[string]bool{}) != "" {
+		t.Fail()
+	}
+}
+
+func TestGetPackageConfigs(t *testing.T) {
+	c := config{
+		Output:  "lists.go",
+		Methods: []string{"Map"},
+		Options: map[string]interface{}{"receiver": "auto", "copy": true},
+		Packages: []config{
+			{Dir: "models", Package: "models"},
+			{Dir: "api", Methods: []string{"Each"}, Output: "api_lists.go", Options: map[string]interface{}{"copy": false}},
+		},
+	}
+	packages := getPackageConfigs("config/fungen.json", c)
+
+	if len(packages) != 2 || packages[0].Output != "config/models/lists.go" || packages[0].Methods[0] != "Map" || packages[0].Options["copy"] != true {
+		t.Fail()
+	}
+	if packages[1].Package != "api" || packages[1].Output != "config/api/api_lists.go" || packages[1].Methods[0] != "Each" || packages[1].Options["copy"] != false || packages[1].Options["receiver"] != "auto" {
+		t.Fail()
+	}
+}
+
+func TestInit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(dir+"/types.go", []byte("package models\n\ntype Users []User\n"), 0644)
+
+	c := getInitConfig(dir)
+	if c.Package != "models" || len(c.Types) != 1 || c.Types[0].Type != "User" || c.Types[0].List != "Users" || c.Options["discover"] != true {
+		t.Fail()
+	}
+	if getInitDirectiveSource(dir, "models") != "package models\n\n//go:generate fungen\n" {
+		t.Fail()
+	}
+	ioutil.WriteFile(dir+"/generate.go", []byte("package models\n\n//go:generate fungen\n"), 0644)
+	if getInitDirectiveSource(dir, "models") != "" {
+		t.Fail()
+	}
+}
+
+func TestCheckPackageType(t *testing.T) {
+	if _, err := checkPackageType("map[string]Generator"); err != nil {
+		t.Fail()
+	}
+	if _, err := checkPackageType("Generatr"); err == nil || err.Error() != "undefined: Generatr" {
+		t.Fail()
+	}
+	if _, err := checkPackageType("map[[]int]Generator"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestCheckGeneratedFiles(t *testing.T) {
+	if err := checkGeneratedFiles(map[string]string{"fungen_typecheck.go": "package fungen\n\nvar _ Generator\n"}); err != nil {
+		t.Fail()
+	}
+	err := checkGeneratedFiles(map[string]string{"fungen_typecheck.go": "package fungen\n\nvar _ Generatr\n"})
+	if err == nil || !strings.Contains(err.Error(), "undefined: Generatr") || !strings.Contains(err.Error(), ">    3 | var _ Generatr\n") {
+		t.Fail()
+	}
+}
+
+func TestGetVetTags(t *testing.T) {
+	if getVetTags("") != nil || getVetTags("!js") != nil {
+		t.Fail()
+	}
+	if strings.Join(getVetTags("linux && (amd64 || arm64)"), ",") != "linux,amd64,arm64" {
+		t.Fail()
+	}
+}
+
+func TestGetDefaultPackageName(t *testing.T) {
+	if getDefaultPackageName() != "fungen" {
+		t.Fail()
+	}
+
+	os.Setenv("GOPACKAGE", "models")
+	defer os.Unsetenv("GOPACKAGE")
+	if getDefaultPackageName() != "models" {
+		t.Fail()
+	}
+}
+
+func TestGetImportsSource(t *testing.T) {
+	src := `package main
+
+import (
+	"sort"
+	"sync"
+	uuid "github.com/gofrs/uuid"
+)
+
+import "time"
+
+func f(l []uuid.UUID) { sort.Slice(l, nil); fmt.Println(strings.Title("")) }
+`
+	expected := `package main
+
+import (
+	"fmt"
+	"sort"
+	uuid "github.com/gofrs/uuid"
+)
+
+func f(l []uuid.UUID) { sort.Slice(l, nil); fmt.Println(strings.Title("")) }
+`
+
+	if getImportsSource(src, map[string]string{"fmt": `"fmt"`}) != f(expected) || getSplitFileName("User") != "fungen_user.go" {
+		t.Fail()
+	}
+}
+
+func TestGetPlan(t *testing.T) {
+	*types, *pairs, *methods, *split = "int:Int,string", "int,string", "Map,Sum", true
+	defer func() { *types, *pairs, *methods, *split = "", "", "", false }()
+	g, err := prepareGeneration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := getPlan(g)
+
+	if len(result.Files) != 3 || result.Files[0].Name != "fungen_int.go" || result.Files[2].Name != "fungen_intstringpair.go" {
+		t.Fatal(result.Files)
+	}
+	if strings.Join(result.Files[0].Lists[0].Methods, ",") != "Map,MapString,Sum" || strings.Join(result.Files[1].Lists[0].Methods, ",") != "MapInt,Map" {
+		t.Fail()
+	}
+	if pair := result.Files[2].Lists[0].Pair; pair == nil || pair.First != "int" || pair.Second != "string" {
+		t.Fail()
+	}
+}
+
+func TestSplitStandardOutput(t *testing.T) {
+	*types, *split, *outputName = "int", true, "-"
+	defer func() { *types, *split, *outputName = "", false, "fungen_auto.go" }()
+	if _, err := prepareGeneration(); err == nil || !strings.Contains(err.Error(), "-split cannot be used with the standard output") {
+		t.Fatal(err)
+	}
+}
+
+func TestGetCompletion(t *testing.T) {
+	flags := []*flag.Flag{flagSet.Lookup("methods"), flagSet.Lookup("split"), flagSet.Lookup("config")}
+	commandNames, methodNames := []string{"check", "generate"}, []string{"Map", "Filter"}
+
+	bash := getBashCompletion(commandNames, flags, methodNames)
+	if !strings.Contains(bash, "-methods|--methods)") || !strings.Contains(bash, `-W "Map Filter"`) || !strings.Contains(bash, `-W "-methods -split -config"`) {
+		t.Fail()
+	}
+	zsh := getZshCompletion(commandNames, flags, methodNames)
+	if !strings.Contains(zsh, "methods=(Map Filter)") || !strings.Contains(zsh, "'-config[Path of a JSON configuration file declaring the package, types, methods and options]:path:_files'") {
+		t.Fail()
+	}
+	fish := getFishCompletion(commandNames, flags, methodNames)
+	if !strings.Contains(fish, "complete -c fungen -o split -d 'Whether to generate one file per type") || !strings.Contains(fish, "-a 'check generate'") {
+		t.Fail()
+	}
+}
+
+func TestGetGofumptSource(t *testing.T) {
+	src := `package main
+
+import (
+	uuid "github.com/gofrs/uuid"
+	"sort"
+)
+
+//go:generate fungen -types int
+
+func f(l []uuid.UUID) error {
+
+	var n = len(l)
+	sort.Slice(l, nil) //comment
+	err := g(n)
+
+	if err != nil {
+		return err
+	}
+	return nil
+
+}
+`
+	expected := `package main
+
+import (
+	"sort"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+//go:generate fungen -types int
+
+func f(l []uuid.UUID) error {
+	n := len(l)
+	sort.Slice(l, nil) // comment
+	err := g(n)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+`
+
+	if getGofumptSource(src) != expected || getGofumptSource(expected) != expected {
+		t.Fail()
+	}
+}
+
+func TestGetStaleness(t *testing.T) {
+	file, err := ioutil.TempFile("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("package main\n\ntype intList []int\n")
+	file.Close()
+
+	if getStaleness(file.Name(), "package main\n\ntype intList []int\n") != "" {
+		t.Fail()
+	}
+	if getStaleness(file.Name(), "package main\n\ntype IList []int\n") != file.Name()+" differs from line 3 (4 lines, 4 expected)" {
+		t.Fail()
+	}
+	if getStaleness(file.Name()+".missing", "") != file.Name()+".missing is missing" {
+		t.Fail()
+	}
+}
+
+func TestGetWatchState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	*outputDir = dir
+	defer func() { *outputDir = "" }()
+
+	ioutil.WriteFile(dir+"/types.go", []byte("package models\n"), 0644)
+	state := getWatchState()
+	ioutil.WriteFile(dir+"/fungen_auto.go", []byte("// Package models - "+generatedMarker+"\npackage models\n"), 0644)
+	if getWatchState() != state {
+		t.Fail()
+	}
+	ioutil.WriteFile(dir+"/types.go", []byte("package models\n\ntype User struct{}\n"), 0644)
+	if getWatchState() == state {
+		t.Fail()
+	}
+}
+
+func TestOutputDirPackage(t *testing.T) {
+	resetState()
+	defer resetState()
+	dir, _ := ioutil.TempDir("", "fungen")
+	defer os.RemoveAll(dir)
+	os.MkdirAll(dir+"/sub", 0755)
+	ioutil.WriteFile(dir+"/sub/foo.go", []byte("package q\n\ntype Foo struct{ ID int }\n"), 0644)
+
+	if err := runMain([]string{"-outdir", dir + "/sub", "-types", "Foo", "-methods", "Map,Contains"}); err != nil {
+		t.Fatal(err)
+	}
+	src, _ := ioutil.ReadFile(dir + "/sub/fungen_auto.go")
+	if !strings.Contains(string(src), "\npackage q\n") || !strings.Contains(string(src), "func (l FooList) Contains(") {
+		t.Fatal(string(src))
+	}
+}
+
+func TestGetUnifiedDiff(t *testing.T) {
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	after := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	expected := `--- a/x.go
++++ b/x.go
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -8,3 +8,4 @@
+ h
+ i
+ j
++k
+`
+
+	if getUnifiedDiff("a/x.go", "b/x.go", before, after) != expected || getUnifiedDiff("a/x.go", "b/x.go", before, before) != "" {
+		t.Fail()
+	}
+}
+
+func TestWriteOutputUnchanged(t *testing.T) {
+	file, err := ioutil.TempFile("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("package main\n")
+	file.Close()
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	os.Chtimes(file.Name(), past, past)
+
+	writeOutput(file.Name(), "package main\n")
+	if info, err := os.Stat(file.Name()); err != nil || !info.ModTime().Equal(past) {
+		t.Fail()
+	}
+
+	writeOutput(file.Name(), "package models\n")
+	if content, err := ioutil.ReadFile(file.Name()); err != nil || string(content) != "package models\n" {
+		t.Fail()
+	}
+}
+
+func TestGenerateOrder(t *testing.T) {
+	m := map[string]string{"int": "int", "string": "string", "bool": "bool", "float64": "float64"}
+	result := generate("int", "intList", m, map[string]bool{"Map": true})
+
+	if !(strings.Index(result, ") MapBool(") < strings.Index(result, ") MapFloat64(") && strings.Index(result, ") MapFloat64(") < strings.Index(result, ") Map(") && strings.Index(result, ") Map(") < strings.Index(result, ") MapString(")) {
+		t.Fail()
+	}
+	if strings.Join(getSortedKeys(m), ",") != "bool,float64,int,string" {
+		t.Fail()
+	}
+}
+
+func TestGenerateTests(t *testing.T) {
+	m := map[string]string{"int": "int", "string": "String"}
+	result := generateTests(testTemplates, "int", "intList", m, map[string]bool{"Map": true, "Take": true, "FilterMap": true})
+
+	if !strings.Contains(result, "func TestIntListMap(t *testing.T)") || !strings.Contains(result, "func TestIntListMapString(t *testing.T)") || !strings.Contains(result, "func TestIntListFilterMapString(t *testing.T)") {
+		t.Fail()
+	}
+	if strings.Contains(result, "func TestIntListFilterMap(t *testing.T)") || !strings.Contains(result, "if &got[0] != first {") {
+		t.Fail()
+	}
+	if getTestFileName("fungen_auto.go", "_test.go") != "fungen_auto_test.go" {
+		t.Fail()
+	}
+}
+
+func TestGenerateExamples(t *testing.T) {
+	m := map[string]string{"int": "int"}
+	result := generateTests(exampleTemplates, "int", "IntList", m, map[string]bool{"Map": true, "Sum": true})
+
+	if !strings.Contains(result, "func ExampleIntList_Map() {") || !strings.Contains(result, "// Output: [6 2 4]") || !strings.Contains(result, "// Output: 6") {
+		t.Fail()
+	}
+	if generateTests(exampleTemplates, "int", "intList", m, map[string]bool{"Map": true}) != "" {
+		t.Fail()
+	}
+	if getTestFileName("fungen_auto.go", "_example_test.go") != "fungen_auto_example_test.go" {
+		t.Fail()
+	}
+}
+
+func TestGenerateFuzzTarget(t *testing.T) {
+	m := map[string]string{"int": "int"}
+	result := generateFuzzTarget("int", "IntList", m, map[string]bool{"Map": true, "PMap": true, "Take": true, "Drop": true})
+
+	if !strings.Contains(result, "func FuzzIntList(f *testing.F) {") || !strings.Contains(result, "l.PMap(fn), l.Map(fn)") || !strings.Contains(result, "l.Take(n), l.Drop(n)") {
+		t.Fail()
+	}
+	if strings.Contains(result, "pred :=") {
+		t.Fail()
+	}
+	if generateFuzzTarget("int", "IntList", m, map[string]bool{"Sum": true, "Take": true}) != "" {
+		t.Fail()
+	}
+}
+
+func TestGeneratePropertyTests(t *testing.T) {
+	m := map[string]string{"string": "string", "User": "User"}
+	result := generatePropertyTests("string", "StrList", m, map[string]bool{"Reduce": true, "ReduceRight": true})
+
+	if !strings.Contains(result, "func TestStrListProperties(t *testing.T) {") || !strings.Contains(result, "return a + b") || strings.Contains(result, "mapComposition") {
+		t.Fail()
+	}
+	if generatePropertyTests("string", "StrList", m, map[string]bool{"Reduce": true}) != "" {
+		t.Fail()
+	}
+	if generatePropertyTests("User", "UserList", m, map[string]bool{"Map": true}) != "" {
+		t.Fail()
+	}
+}
+
+func TestGetBuildConstraint(t *testing.T) {
+	header := getBuildConstraint("!js && (linux || darwin)")
+	if header != "//go:build !js && (linux || darwin)\n// +build !js\n// +build linux darwin\n\n" {
+		t.Fail()
+	}
+	if getBuildConstraint("") != "" {
+		t.Fail()
+	}
+	if !isGeneratedSource([]byte(header + "// Package main - " + generatedMarker + "\npackage main\n")) {
+		t.Fail()
+	}
+	if isGeneratedSource([]byte("package main\n\n// " + generatedMarker + "\n")) {
+		t.Fail()
+	}
+}
+
+func TestGetFileHeader(t *testing.T) {
+	file, err := ioutil.TempFile("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("// Copyright Example\n// SPDX-License-Identifier: MIT\n\n")
+	file.Close()
+
+	if getFileHeader(file.Name()) != "// Copyright Example\n// SPDX-License-Identifier: MIT\n\n" || getFileHeader("") != "" {
+		t.Fail()
+	}
+}
+
+func TestGetCommandLine(t *testing.T) {
+	flags := flag.NewFlagSet("fungen", flag.ContinueOnError)
+	flags.String("types", "", "")
+	flags.String("package", "main", "")
+	flags.Bool("split", false, "")
+	flags.Bool("check", false, "")
+	flags.String("cpuprofile", "", "")
+	flags.String("filename", "fungen_auto.go", "")
+	flags.Parse([]string{"-types", "int,*User:U", "-split", "-check", "-package", "models", "-cpuprofile", "cpu.prof", "-filename", "-"})
+
+	commandLine = getCommandLine(flags)
+	defer func() { commandLine = "" }()
+	if commandLine != `fungen -package models -split=true -types "int,*User:U"` {
+		t.Fail()
+	}
+	if !strings.Contains(getProvenance(), "\n//\t//go:generate "+commandLine) {
+		t.Fail()
+	}
+	flags.Set("filename", "lists.go")
+	if getCommandLine(flags) != `fungen -filename lists.go -package models -split=true -types "int,*User:U"` {
+		t.Fail()
+	}
+}
+
+func TestGetVersionInfo(t *testing.T) {
+	info := getVersionInfo()
+	if !strings.HasPrefix(info, "fungen "+getVersion()+" (") || !strings.Contains(info, "\nmethods: Map,PMap,Filter,") || !strings.Contains(info, "\nopt-in methods: Heap,String,") {
+		t.Fail()
+	}
+}
+
+func TestGetMethodInfo(t *testing.T) {
+	info := getMethodInfo(generators.Filter(func(gen Generator) bool { return gen.Name == "GroupBy" })[0])
+	if info.Name != "GroupBy" || info.Requires != "comparable" || !info.CrossType || info.OptIn || len(info.Imports) != 0 {
+		t.Fail()
+	}
+	info = getMethodInfo(generators.Filter(func(gen Generator) bool { return gen.Name == "PMap" })[0])
+	if info.Requires != "" || !info.CrossType || strings.Join(info.Imports, ",") != "sync" {
+		t.Fail()
+	}
+}
+
+func TestGetMethodsMapExcluded(t *testing.T) {
+	*excluded = "PMap,PFilter"
+	defer func() { *excluded = "" }()
+
+	all := getMethodsMap("")
+	if all["PMap"] || all["PFilter"] || !all["Map"] || all["Heap"] {
+		t.Fail()
+	}
+	selected := getMethodsMap("Map,PMap,Heap")
+	if len(selected) != 2 || !selected["Map"] || !selected["Heap"] {
+		t.Fail()
+	}
+}
+
+func TestGetTypeMapMethods(t *testing.T) {
+	defer func() { typeMethods = map[string]string{} }()
+	m := getTypeMap("User:U::Map,Filter;int:I::all;string")
+	if len(m) != 3 || m["User"] != "U" || m["int"] != "I" || typeMethods["User"] != "Map,Filter" {
+		t.Fail()
+	}
+	if methods, ok := typeMethods["int"]; !ok || methods != "" {
+		t.Fail()
+	}
+	if _, ok := typeMethods["string"]; ok {
+		t.Fail()
+	}
+}
+
+func TestGetAppendedSource(t *testing.T) {
+	file, err := ioutil.TempFile("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.Close()
+	defer func() { commandLine = "" }()
+
+	header := "// Package main - " + generatedMarker + "\npackage main\n\n"
+	commandLine = "fungen -append=true -types int"
+	src := getAppendedSource(file.Name(), header+"import \"sort\"\n\ntype intList []int\n\nfunc (l intList) Sort() { sort.Ints(l) }\n")
+	ioutil.WriteFile(file.Name(), []byte(src), 0644)
+	commandLine = "fungen -append=true -types string"
+	src = getAppendedSource(file.Name(), header+"import \"strings\"\n\ntype stringList []string\n\nfunc (l stringList) Join() string { return strings.Join(l, \"\") }\n")
+	ioutil.WriteFile(file.Name(), []byte(src), 0644)
+	commandLine = "fungen -append=true -types int"
+	src = getAppendedSource(file.Name(), header+"type intList []int\n")
+	ioutil.WriteFile(file.Name(), []byte(src), 0644)
+	commandLine = "fungen -append=true -methods Map -types int"
+	src = getAppendedSource(file.Name(), header+"type intList []int\n")
+
+	expected := header + `import (
+	"strings"
+)
+
+// fungen:begin fungen -append=true -methods Map -types int
+type intList []int
+// fungen:end
+
+// fungen:begin fungen -append=true -types string
+type stringList []string
+
+func (l stringList) Join() string { return strings.Join(l, "") }
+// fungen:end
+`
+	if src != f(expected) {
+		t.Fail()
+	}
+
+	ioutil.WriteFile(file.Name(), []byte(src), 0644)
+	commandLine = "fungen -append=true -types int,bool"
+	err = func() (err error) {
+		defer recoverFatal(&err)
+		getAppendedSource(file.Name(), header+"type intList []int\n\ntype boolList []bool\n")
+		return nil
+	}()
+	if err == nil || !strings.Contains(err.Error(), "'intList' is already generated") {
+		t.Fatal(err)
+	}
+}
+
+func TestGetMergedSource(t *testing.T) {
+	file, err := ioutil.TempFile("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	header := "// Package main - " + generatedMarker + "\npackage main\n\n"
+	file.WriteString(header + `import "sort"
+
+// intList - ints
+type intList []int
+
+func (l intList) Len() int { return len(l) }
+
+// stringList - strings
+type stringList []string
+
+// Sort - sort
+func (l stringList) Sort() { sort.Strings(l) }
+
+// StringLister - interface
+type StringLister interface {
+	Sort()
+}
+
+var _ StringLister = stringList(nil)
+
+func SortStringList(l []string) { sort.Strings(l) }
+`)
+	file.Close()
+
+	expected := header + `import (
+	"sort"
+)
+
+type intList []int
+
+// stringList - strings
+type stringList []string
+
+// Sort - sort
+func (l stringList) Sort() { sort.Strings(l) }
+
+// StringLister - interface
+type StringLister interface {
+	Sort()
+}
+
+var _ StringLister = stringList(nil)
+
+func SortStringList(l []string) { sort.Strings(l) }
+`
+	if getMergedSource(file.Name(), header+"type intList []int\n") != f(expected) {
+		t.Fail()
+	}
+	if getMergedSource(file.Name()+".missing", header) != header {
+		t.Fail()
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func() { methodTemplates = map[string]*template.Template{} }()
+	ioutil.WriteFile(dir+"/Map.tmpl", []byte(`{{define "imports"}}log{{end}}// Map{{.TargetName}} on {{.List}} of {{.Type}} to {{.TargetList}} of {{.TargetType}}`), 0644)
+	loadTemplates(dir)
+
+	result := generate("int", "intList", map[string]string{"int": "int", "string": "string"}, map[string]bool{"Map": true, "Filter": true})
+	if !strings.Contains(result, "\n// Map on intList of int to intList of int\n") || !strings.Contains(result, "\n// MapString on intList of int to stringList of string\n") || !strings.Contains(result, ") Filter(") {
+		t.Fail()
+	}
+	if strings.Join(getTemplateImports(), ",") != "log" {
+		t.Fail()
+	}
+}
+
+func TestAddTemplateGenerator(t *testing.T) {
+	defer func(saved GeneratorList) { generators = saved }(generators)
+	addTemplateGenerator("IndexOf", "// IndexOf{{.TargetName}} on {{.List}}", false, true, []string{"log"}, "comparable")
+	addTemplateGenerator("Pairs", "{{if .TargetName}}// Pairs{{.TargetName}} on {{.List}} to {{.TargetList}}{{end}}", true, false, nil, "")
+
+	m := map[string]string{"int": "int", "[]int": "Ints"}
+	result := generate("int", "intList", m, map[string]bool{"IndexOf": true, "Pairs": true})
+	if !strings.Contains(result, "// IndexOf on intList\n") || !strings.Contains(result, "// PairsInts on intList to IntsList\n") || strings.Contains(result, "// Pairs on") {
+		t.Fail()
+	}
+	if strings.Contains(generate("[]int", "IntsList", m, map[string]bool{"IndexOf": true}), "IndexOf") {
+		t.Fail()
+	}
+	if getMethodsMap("")["IndexOf"] || !getMethodsMap("")["Pairs"] || getImports(map[string]bool{"IndexOf": true}, []string{"int"}) != "import (\n\"log\"\n)" {
+		t.Fail()
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	defer resetState()
+	src, err := Generate(Options{Package: "models", Types: "int:I,string", Methods: "Map,Filter", Flags: map[string]string{"funcs": "true"}})
+	if err != nil || !strings.Contains(string(src), "\npackage models\n") || !strings.Contains(string(src), "func MapIList(l []int, f func(int) int) []int {") || strings.Contains(string(src), "type IList") {
+		t.Fail()
+	}
+
+	src, err = Generate(Options{Types: "int", Methods: "Map"})
+	if err != nil || !strings.Contains(string(src), "type intList []int") || strings.Contains(string(src), "MapIntList") {
+		t.Fail()
+	}
+
+	if _, err := Generate(Options{Types: "int", Flags: map[string]string{"split": "true"}}); err == nil {
+		t.Fail()
+	}
+	if _, err := Generate(Options{Types: "int", Flags: map[string]string{"unknown": "true"}}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestGenerateRestoresFlags(t *testing.T) {
+	defer resetState()
+	set := flagSet
+	flagSet.Set("methods", "Map")
+	if _, err := Generate(Options{Types: "int", Methods: "Filter", Flags: map[string]string{"funcs": "true"}}); err != nil {
+		t.Fatal(err)
+	}
+	if flagSet != set || flagSet.ErrorHandling() != flag.ExitOnError || *methods != "Map" || *funcs || !isFlagSet("methods") || isFlagSet("funcs") {
+		t.Fail()
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	defer resetState()
+	if _, err := Generate(Options{Types: "int", Methods: "Map,Mop"}); err == nil || err.Error() != "fungen: -methods: 'Mop' is not a valid method, did you mean 'Map'?\n\tMap,Mop\n\t    ^^^" {
+		t.Fatal(err)
+	}
+	if _, err := Generate(Options{Types: "map[int"}); err == nil || !strings.HasPrefix(err.Error(), "fungen: -types: 'map[int' is not a valid type") {
+		t.Fatal(err)
+	}
+	if src, err := Generate(Options{Types: "int", Methods: "Map"}); err != nil || len(src) == 0 {
+		t.Fatal(err)
+	}
+
+	if err := writeOutput("missing/fungen_auto.go", "package fungen\n"); err == nil || !strings.HasPrefix(err.Error(), "writing the generated file") {
+		t.Fatal(err)
+	}
+}
+
+func TestRegisterGenerator(t *testing.T) {
+	defer func(saved GeneratorList) { generators = saved }(generators)
+	err := RegisterGenerator("Second", Generator{
+		Method: func(listName, typeName, _, _ string) string {
+			return fmt.Sprintf("\n// Second is a method on %[1]s\nfunc (l %[1]s) Second() %[2]s { return l[1] }\n", listName, typeName)
+		},
+		NeedSync: true,
+	})
+	if err != nil || !strings.Contains(generate("int", "intList", map[string]string{"int": "int"}, getMethodsMap("Second")), "func (l intList) Second() int {") {
+		t.Fail()
+	}
+	if getImports(map[string]bool{"Second": true}, nil) != "import (\n\"sync\"\n)" {
+		t.Fail()
+	}
+	if RegisterGenerator("Second", Generator{Method: generators[0].Method}) == nil || RegisterGenerator("second", Generator{Method: generators[0].Method}) == nil {
+		t.Fail()
+	}
+}
+
+func TestGoVersion(t *testing.T) {
+	if minor, err := getGoMinor("go1.21.3"); err != nil || minor != 21 {
+		t.Fail()
+	}
+	if _, err := getGoMinor("1.x"); err == nil {
+		t.Fail()
+	}
+
+	defer func() { *goVersion = "" }()
+	if src := getSortFunction("intList", "int", "", ""); !strings.Contains(src, "sort.Slice(l,") || strings.Contains(getHeapFunction("intList", "int", "", ""), " any") {
+		t.Fail()
+	}
+	if supportsIter("int", "") {
+		t.Fail()
+	}
+	*goVersion = "1.21"
+	if src := getSortFunction("intList", "int", "", ""); !strings.Contains(src, "slices.Sort(l)") || !strings.Contains(getHeapFunction("intList", "int", "", ""), "Push(x any)") {
+		t.Fail()
+	}
+	if !strings.Contains(getAnyFunction("intList", "int", "", ""), "slices.ContainsFunc(l, f)") || !strings.Contains(getTruncateFunction("intList", "int", "", ""), "clear(l[n:])") {
+		t.Fail()
+	}
+	if supportsIter("int", "") {
+		t.Fail()
+	}
+	*goVersion = "1.23"
+	if !supportsIter("int", "") || !strings.Contains(getValuesFunction("intList", "int", "", ""), "Values() iter.Seq[int]") {
+		t.Fail()
+	}
+}
+
+func TestGetExportedName(t *testing.T) {
+	defer func() { *nameCase = "initialisms" }()
+	if getExportedName("url") != "URL" || getExportedName("apiID") != "APIID" || getExportedName("userID") != "UserID" || getExportedName("utf8") != "UTF8" || getExportedName("éclair") != "Éclair" || getExportedName("int") != "Int" {
+		t.Fail()
+	}
+	*nameCase = "title"
+	if getExportedName("url") != "Url" || getExportedName("apiID") != "ApiID" {
+		t.Fail()
+	}
+	if getFuncName("New", "Éclair", "") != "NewÉclair" || getFuncName("New", "éclair", "") != "newÉclair" {
+		t.Fail()
+	}
+}
+
+func TestGetCollisions(t *testing.T) {
+	typeMap := map[string]string{"int": "A", "string": "a", "float64": "float64"}
+	methodsMap := map[string]bool{"Map": true, "FilterMapTo": true}
+	typeMethodsMaps := map[string]map[string]bool{"int": methodsMap, "string": methodsMap, "float64": methodsMap}
+	collisions := getCollisions(typeMap, typeMethodsMaps, nil)
+	if len(collisions) != 2 || collisions[0] != "the methods Map to 'int' and Map to 'string' of the list type 'float64List' are both named 'MapA'" {
+		t.Fatal(collisions)
+	}
+
+	typeMap = map[string]string{"int": "int", "string": "To"}
+	typeMethodsMaps = map[string]map[string]bool{"int": {"FilterMap": true, "FilterMapTo": true}, "string": {}}
+	if collisions := getCollisions(typeMap, typeMethodsMaps, [][2]string{{"int", "string"}, {"int", "string"}}); len(collisions) != 2 || !strings.HasPrefix(collisions[0], "the pair struct of 'int,string' and") {
+		t.Fatal(collisions)
+	}
+}
+
+func TestResolveConflicts(t *testing.T) {
+	*onConflict = "suffix"
+	defer func() { *onConflict = "error" }()
+	files := map[string]string{
+		"fungen_conflict.go":      "package fungen\n\n// Generator is generated\ntype Generator []int\n\nfunc (l Generator) Len() int {\n\treturn len(l)\n}\n",
+		"fungen_conflict_test.go": "package fungen\n\nfunc useGenerator(g Generator) int {\n\treturn g.Len()\n}\n",
+	}
+	resolveConflicts(files)
+	if !strings.Contains(files["fungen_conflict.go"], "// Generator2 is generated\ntype Generator2 []int") || !strings.Contains(files["fungen_conflict.go"], "func (l Generator2) Len() int") {
+		t.Fatal(files["fungen_conflict.go"])
+	}
+	if !strings.Contains(files["fungen_conflict_test.go"], "func useGenerator(g Generator2) int {") {
+		t.Fatal(files["fungen_conflict_test.go"])
+	}
+}
+
+func TestLenient(t *testing.T) {
+	defer resetState()
+	if _, err := Generate(Options{Types: "int,Generator", Methods: "Map,Sum,Mop"}); err == nil {
+		t.Fail()
+	}
+
+	src, err := Generate(Options{Types: "int,Generator", Methods: "Map,Sum,Mop", Flags: map[string]string{"lenient": "true"}})
+	if err != nil || !strings.Contains(string(src), "func (l intList) Sum() int {") || strings.Contains(string(src), "func (l GeneratorList) Sum()") {
+		t.Fatal(err)
+	}
+	if len(skippedProblems) != 2 || !strings.HasPrefix(skippedProblems[0], "-methods: 'Mop' is not a valid method") || skippedProblems[1] != "GeneratorList.Sum is not generated, it does not support the members of type Generator" {
+		t.Fatal(skippedProblems)
+	}
+
+	resetState()
+	if _, err := Generate(Options{Pairs: "int,Nope;int,string", Methods: "Map"}); err == nil {
+		t.Fail()
+	}
+	src, err = Generate(Options{Pairs: "int,Nope;int,string", Methods: "Map", Flags: map[string]string{"lenient": "true"}})
+	if err != nil || !strings.Contains(string(src), "type intStringPair struct {") || strings.Contains(string(src), "intNopePair") {
+		t.Fatal(err)
+	}
+	if len(skippedProblems) != 1 || !strings.HasPrefix(skippedProblems[0], "-pairs type 'Nope' cannot be used") {
+		t.Fatal(skippedProblems)
+	}
+}
+
+func TestUnsupportedExplicitMethods(t *testing.T) {
+	defer resetState()
+	for options, message := range map[string]string{
+		"Generator::GL:Map,Sum;int": "GL.Sum is not generated, it does not support the members of type Generator",
+		"Generator":                 "-methods: Sum is not generated, it does not support the members of any of the types Generator",
+	} {
+		if _, err := Generate(Options{Types: options, Methods: "Sum"}); err == nil || !strings.Contains(err.Error(), message) {
+			t.Fatal(options, err)
+		}
+		resetState()
+		src, err := Generate(Options{Types: options, Methods: "Sum", Flags: map[string]string{"lenient": "true"}})
+		if err != nil || strings.Contains(string(src), "Generator) Sum()") || len(skippedProblems) == 0 || skippedProblems[0] != message {
+			t.Fatal(options, err, skippedProblems)
+		}
+		resetState()
+	}
+}
+
+func TestStartProfiling(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "fungen")
+	defer os.RemoveAll(dir)
+	*cpuProfile, *memProfile = dir+"/cpu.prof", dir+"/mem.prof"
+	defer func() { *cpuProfile, *memProfile = "", "" }()
+
+	stop := startProfiling()
+	generate("int", "intList", map[string]string{"int": "int"}, map[string]bool{"Map": true})
+	stop()
+	for _, path := range []string{*cpuProfile, *memProfile} {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Fatal(path, err)
+		}
+	}
+}
+
+func TestCheckExitStatus(t *testing.T) {
+	defer resetState()
+	dir, _ := ioutil.TempDir("", "fungen")
+	defer os.RemoveAll(dir)
+	stderr := os.Stderr
+	os.Stderr, _ = os.Open(os.DevNull)
+	defer func() { os.Stderr = stderr }()
+
+	err := runMain([]string{"-types", "int", "-methods", "Map", "-package", "main", "-check", "-filename", dir + "/fungen_auto.go", "-cpuprofile", dir + "/cpu.prof"})
+	if exitErr, ok := err.(exitError); !ok || exitErr.code != 1 {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(dir + "/cpu.prof"); err != nil || info.Size() == 0 {
+		t.Fatal("the profile is not written before exiting", err)
+	}
+}
+
+// captureStdout - get what fn prints to the standard output
+func captureStdout(fn func()) string {
+	stdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	output := make(chan string)
+	go func() {
+		b, _ := ioutil.ReadAll(r)
+		output <- string(b)
+	}()
+	fn()
+	w.Close()
+	return <-output
+}
+
+func TestCommands(t *testing.T) {
+	defer resetState()
+	dir, _ := ioutil.TempDir("", "fungen")
+	defer os.RemoveAll(dir)
+	stderr := os.Stderr
+	os.Stderr, _ = os.Open(os.DevNull)
+	defer func() { os.Stderr = stderr }()
+	args := []string{"-types", "int", "-methods", "Map", "-package", "main", "-filename", dir + "/fungen_auto.go"}
+
+	resetState()
+	if err := runMain(append([]string{"generate"}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	if src, err := ioutil.ReadFile(dir + "/fungen_auto.go"); err != nil || !strings.Contains(string(src), "func (l intList) Map(") {
+		t.Fatal(err)
+	}
+
+	resetState()
+	if err := runMain(append([]string{"check"}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(dir+"/fungen_auto.go", []byte("package main\n"), 0644)
+	resetState()
+	if exitErr, ok := runMain(append([]string{"check"}, args...)).(exitError); !ok || exitErr.code != 1 {
+		t.Fatal("check does not exit with status 1 for a stale file")
+	}
+
+	resetState()
+	output := captureStdout(func() {
+		if err := runMain([]string{"-types", "int", "-methods", "Map", "-filename", "-"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(output, "\npackage fungen\n") || !strings.Contains(output, "func (l intList) Map(") {
+		t.Fatal(output)
+	}
+
+	resetState()
+	output = captureStdout(func() { runMain([]string{"list-methods"}) })
+	if lines := strings.Split(output, "\n"); len(lines) != len(generators)+1 || lines[0] != "Map" || lines[len(lines)-1] != "" {
+		t.Fatal(output)
+	}
+	output = captureStdout(func() { runMain([]string{"list-methods", "-opt-in"}) })
+	if strings.Contains(output, "Map\n") || !strings.Contains(output, "\nString\n") {
+		t.Fatal(output)
+	}
+
+	if output = captureStdout(func() { runMain([]string{"version"}) }); output != getVersionInfo() {
+		t.Fatal(output)
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	if s := didYouMean("Fliter", getMethodNames()); s != ", did you mean 'Filter'?" {
+		t.Fatal(s)
+	}
+	if s := didYouMean("Zzz", getMethodNames()); s != "" {
+		t.Fatal(s)
+	}
+	if name := getUndefinedFlag(flagSet, []string{"-types", "int", "-metods", "Map"}); name != "metods" {
+		t.Fatal(name)
+	}
+	if s := getSuggestion("metods", getFlagNames()); s != "methods" {
+		t.Fatal(s)
+	}
+	for typo, name := range map[string]string{"Mpa": "Map", "Tkae": "Take", "PMpa": "PMap"} {
+		if s := getSuggestion(typo, getMethodNames()); s != name {
+			t.Fatal(typo, s)
+		}
+	}
+	if name := getUndefinedFlag(flagSet, []string{"-tpyes", "int"}); name != "tpyes" || getSuggestion(name, getFlagNames()) != "types" {
+		t.Fatal(name)
+	}
+	if getEditDistance("ab", "ba") != 1 || getEditDistance("ca", "abc") != 3 || getEditDistance("Fliter", "filter") != 1 {
+		t.Fail()
+	}
+
+	content := []byte("{\n  \"types\": [\"int\",]\n}")
+	var c config
+	err := json.Unmarshal(content, &c)
+	if s := getConfigErrorDetails(content, err); !strings.HasPrefix(s, " at line 2, column ") || !strings.Contains(s, "\n\t  \"types\": [\"int\",]\n\t") {
+		t.Fatal(s)
+	}
+}
+
+func TestGenerateDocSource(t *testing.T) {
+	files := map[string]string{"fungen_auto.go": `package fungen
+
+// UserList is the type for a list of users
+type UserList []User
+
+// Map is a method on UserList that takes a function of type User -> User and applies it. It returns a new list.
+func (l UserList) Map(f func(User) User) UserList { return nil }
+
+type intList []int
+
+// Sum is a method on intList that returns the sum of the members
+func (l intList) Sum() int { return 0 }
+`, "fungen_auto_test.go": "package fungen\n\n// UserListTest - test\ntype UserListTest struct{}\n"}
+	src := generateDocSource(files)
+	if !strings.Contains(src, "// UserList is the type for a list of users.\n") ||
+		!strings.Contains(src, "//   - [UserList.Map]: takes a function of type User -> User and applies it\n") ||
+		strings.Contains(src, "intList") || strings.Contains(src, "UserListTest") || getDocFileName("fungen_auto.go") != "fungen_auto_doc.go" {
+		t.Fatal(src)
+	}
+}
+
+func TestGenerateMarkdownReference(t *testing.T) {
+	files := map[string]string{"fungen_auto.go": `package fungen
+
+// intList is the type for a list that holds members of type int
+type intList []int
+
+// Sum is a method on intList that returns the sum of the members
+func (l intList) Sum() int { return 0 }
+
+// newIntList returns a new intList
+func newIntList() intList { return nil }
+`, "fungen_auto_test.go": "package fungen\n\n// UserListTest - test\ntype UserListTest struct{}\n"}
+	md := generateMarkdownReference(files)
+	if !strings.Contains(md, "- [intList](#intlist)\n") ||
+		!strings.Contains(md, "\n## intList\n\n```go\ntype intList []int\n```\n\nintList is the type for a list that holds members of type int\n") ||
+		!strings.Contains(md, "\n### intList.Sum\n\n```go\nfunc (l intList) Sum() int\n```\n\nSum is a method on intList that returns the sum of the members\n") ||
+		!strings.Contains(md, "\n## Functions\n\n### newIntList\n") || strings.Contains(md, "UserListTest") || !isGeneratedSource([]byte(md)) {
+		t.Fatal(md)
+	}
+}
+
+func TestLintSource(t *testing.T) {
+	*nolint, *coverIgnore = "unused,gocyclo", "//coverage:ignore"
+	defer func() { *nolint, *coverIgnore = "", "" }()
+	src := "// Package fungen - generated\npackage fungen\n\nimport \"fmt\"\n\n// intList is a list\ntype intList []int\n\nfunc (l intList) String() string { return fmt.Sprint([]int(l)) }\n"
+
+	result := getLintSource("fungen_auto.go", src)
+	if !strings.Contains(result, "//coverage:ignore\npackage fungen\n") || strings.Count(result, "//nolint:unused,gocyclo\n") != 2 ||
+		!strings.Contains(result, "// intList is a list\n//\n//nolint:unused,gocyclo\ntype intList []int\n") {
+		t.Fatal(result)
+	}
+	if result := getLintSource("fungen_auto_test.go", src); strings.Contains(result, "//coverage:ignore") {
+		t.Fatal(result)
+	}
+
+	*nolint = "all"
+	if getNolintDirective(*nolint) != "//nolint" {
+		t.Fail()
+	}
+	err := func() (err error) {
+		defer recoverFatal(&err)
+		getNolintDirective("unused,Go Vet")
+		return nil
+	}()
+	if err == nil || !strings.HasPrefix(err.Error(), "-nolint: 'Go Vet' is not a valid linter name") {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "fungen")
+	defer os.RemoveAll(dir)
+	fileName := dir + "/fungen_auto.go"
+	src := strings.Repeat("// generated\n", outputBufferSize/5)
+
+	if isUpToDate(fileName, src) {
+		t.Fatal("missing file is up to date")
+	}
+	if err := writeFile(fileName, src); err != nil {
+		t.Fatal(err)
+	}
+	os.Chmod(fileName, 0600)
+	if !isUpToDate(fileName, src) || isUpToDate(fileName, src+"\n") || isUpToDate(fileName, strings.Replace(src, "generated", "Generated", 1)) {
+		t.Fatal("wrong comparison")
+	}
+	if err := writeFile(fileName, "package fungen\n"); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(fileName)
+	files, _ := ioutil.ReadDir(dir)
+	if content, _ := ioutil.ReadFile(fileName); string(content) != "package fungen\n" || info.Mode().Perm() != 0600 || len(files) != 1 {
+		t.Fatal(string(content), info.Mode(), len(files))
+	}
+}
+
+func TestKeptSource(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "fungen")
+	defer os.RemoveAll(dir)
+	fileName := dir + "/fungen_auto.go"
+	region := "// fungen:keep-begin names\nfunc (l intList) Names() []string {\n\treturn strings.Fields(\"a b\")\n}\n\n// fungen:keep-end\n"
+	ioutil.WriteFile(fileName, []byte("// Package fungen - "+generatedMarker+"\npackage fungen\n\nimport \"strings\"\n\ntype intList []int\n\n"+region), 0644)
+
+	src := getKeptSource(fileName, "// Package fungen - "+generatedMarker+"\npackage fungen\n\ntype intList []int\n")
+	if !strings.Contains(src, region) || !strings.Contains(src, "\t\"strings\"\n") {
+		t.Fatal(src)
+	}
+	if getKeptSource(fileName, src) != src {
+		t.Fatal("the region was added again")
+	}
+
+	err := func() (err error) {
+		defer recoverFatal(&err)
+		getKeptRegions(fileName, "package fungen\n\n// fungen:keep-begin\n")
+		return nil
+	}()
+	if err == nil || !strings.Contains(err.Error(), "is not ended") {
+		t.Fatal(err)
+	}
+}
+
+func TestIsOverwritable(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "fungen")
+	defer os.RemoveAll(dir)
+	fileName := dir + "/fungen_auto.go"
+
+	if !isOverwritable(fileName) || !isOverwritable("-") {
+		t.Fatal("missing file is not overwritable")
+	}
+	ioutil.WriteFile(fileName, []byte("// Package fungen - "+generatedMarker+"\npackage fungen\n"), 0644)
+	if !isOverwritable(fileName) {
+		t.Fatal("generated file is not overwritable")
+	}
+	ioutil.WriteFile(fileName, []byte("package fungen\n\nfunc main() {}\n"), 0644)
+	if isOverwritable(fileName) {
+		t.Fatal("hand-written file is overwritable")
+	}
+}
+
+func TestSubpackage(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "fungen")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(dir+"/go.mod", []byte("module example.com/app\n\ngo 1.18\n"), 0644)
+	if getImportPath(dir) != "example.com/app" || getImportPath(dir+"/models") != "example.com/app/models" {
+		t.Fatal(getImportPath(dir + "/models"))
+	}
+
+	*subpackage = "lists"
+	parentPackage.name, parentPackage.path = "fungen", "github.com/kulshekhar/fungen/fungen"
+	defer func() { *subpackage = "" }()
+	files := map[string]string{"fungen_auto.go": `package lists
+
+type GeneratorList []Generator
+
+func (l GeneratorList) Names(f func(Generator) string) []string {
+	names := []string{}
+	for _, g := range l {
+		names = append(names, g.Name)
+	}
+	return names
+}
+`}
+	qualifyParentTypes(files)
+	if !strings.Contains(files["fungen_auto.go"], "\t\"github.com/kulshekhar/fungen/fungen\"\n") || !strings.Contains(files["fungen_auto.go"], "type GeneratorList []fungen.Generator") || !strings.Contains(files["fungen_auto.go"], "f func(fungen.Generator) string") || !strings.Contains(files["fungen_auto.go"], "g.Name") {
+		t.Fatal(files["fungen_auto.go"])
+	}
+	if getOutputPath("fungen_auto.go") != filepath.Join("lists", "fungen_auto.go") {
+		t.Fatal(getOutputPath("fungen_auto.go"))
+	}
+
+	err := func() (err error) {
+		defer recoverFatal(&err)
+		qualifyParentTypes(map[string]string{"fungen_auto.go": "package lists\n\ntype editList []edit\n"})
+		return nil
+	}()
+	if err == nil || !strings.Contains(err.Error(), "'edit' is not exported") {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveImportPath(t *testing.T) {
+	defer func() {
+		fileImportPaths, dependencyImportPaths, standardImportPaths, moduleImportPaths = nil, nil, nil, nil
+	}()
+	if resolveImportPath("ast") != "go/ast" || resolveImportPath("gotypes") != "go/types" || getImportSpec("gotypes", nil) != "gotypes \"go/types\"" {
+		t.Fatal(resolveImportPath("gotypes"))
+	}
+
+	dependencyImportPaths = map[string][]string{"uuid": {"github.com/google/uuid"}, "rand": {"crypto/rand", "math/rand"}}
+	standardImportPaths = map[string][]string{"bytes": {"bytes"}, "uuid": {"uuid"}, "sqlite": {"sqlite"}}
+	moduleImportPaths = map[string][]string{"decimal": {"github.com/shopspring/decimal"}, "sqlite": {"example.com/sqlite"}}
+	if resolveImportPath("uuid") != "github.com/google/uuid" || resolveImportPath("bytes") != "bytes" || resolveImportPath("decimal") != "github.com/shopspring/decimal" {
+		t.Fatal(resolveImportPath("uuid"))
+	}
+	for name, message := range map[string]string{"rand": "crypto/rand, math/rand", "sqlite": "sqlite, example.com/sqlite", "nosuchpkg": "cannot find the package 'nosuchpkg'"} {
+		err := func() (err error) {
+			defer recoverFatal(&err)
+			resolveImportPath(name)
+			return nil
+		}()
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGetModuleImportPaths(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the go command is not installed")
+	}
+	dir, _ := ioutil.TempDir("", "fungen")
+	defer os.RemoveAll(dir)
+	os.MkdirAll(dir+"/app", 0755)
+	os.MkdirAll(dir+"/lib/money", 0755)
+	ioutil.WriteFile(dir+"/app/go.mod", []byte("module example.com/app\n\ngo 1.18\n\nrequire example.com/lib v0.0.0\n\nreplace example.com/lib => ../lib\n"), 0644)
+	ioutil.WriteFile(dir+"/app/app.go", []byte("package app\n"), 0644)
+	ioutil.WriteFile(dir+"/lib/go.mod", []byte("module example.com/lib\n\ngo 1.18\n"), 0644)
+	ioutil.WriteFile(dir+"/lib/money/money.go", []byte("package money\n\ntype Amount int\n"), 0644)
+
+	defer os.Setenv("GO111MODULE", os.Getenv("GO111MODULE"))
+	os.Setenv("GO111MODULE", "on")
+	*outputName = dir + "/app/fungen_auto.go"
+	defer func() { *outputName = "fungen_auto.go" }()
+	if importPaths := getModuleImportPaths(); len(importPaths["money"]) != 1 || importPaths["money"][0] != "example.com/lib/money" {
+		t.Fatal(importPaths)
+	}
+}
+
+func TestStyle(t *testing.T) {
+	*style = "lo"
+	defer func() { *style = "fungen" }()
+	src := getStyledSource(f(`package fungen
+type intList []int
+// Each is a method on intList
+func (l intList) Each(f func(int)) intList { return l }
+func (l intList) EachI(f func(int, int)) intList { return l }
+func (l intList) CountByString(f func(int) string) map[string]int { return nil }
+`), map[string]string{"intList": "int"})
+	if !strings.Contains(src, "// ForEach is a method on intList\nfunc (l intList) ForEach(") || !strings.Contains(src, ") EachI(") || !strings.Contains(src, ") CountValuesByString(") {
+		t.Fatal(src)
+	}
+	if getMethodName(Generator{Name: "Any"}, "") != "SomeBy" || getMethodName(Generator{Name: "Zip"}, "string") != "ZipByString" {
+		t.Fail()
+	}
+	*style = "slices"
+	if getMethodName(Generator{Name: "Any"}, "") != "ContainsFunc" || getMethodName(Generator{Name: "Each"}, "") != "Each" {
+		t.Fail()
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "fungen")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(dir+"/fungen_auto.go", []byte(`// Package main - `+generatedMarker+`
+package main
+
+type intList []int
+
+// Filter is a method on intList
+func (l intList) Filter(f func(int) bool) intList { return l }
+
+// MapString is a method on intList
+func (l intList) MapString(f func(int) string) stringList { return nil }
+
+// PMap is a method on intList
+func (l intList) PMap(f func(int) int) intList { return l }
+`), 0644)
+	ioutil.WriteFile(dir+"/main.go", []byte(`package main
+
+func main() {
+	l := intList{1}
+	_ = l.Filter(func(i int) bool { return i > 0 }).MapString(func(int) string { return "" }).Total()
+	_ = l.PMap(nil)
+}
+
+type stringList []string
+
+func (l stringList) Total() int { return len(l) }
+`), 0644)
+
+	m := migratePackage(dir, "List")
+	if src := m.sources[dir+"/main.go"]; !strings.Contains(src, "_ = stringList(ListMapTo(ListFilter(l, func(i int) bool { return i > 0 }), func(int) string { return \"\" })).Total()") {
+		t.Fatal(src)
+	}
+	if len(m.remaining) != 1 || !strings.HasSuffix(m.remaining[0], "intList.PMap") || m.calls != 2 {
+		t.Fatal(m.remaining, m.calls)
+	}
+	if src := getMigrateHelpersSource(dir+"/fungen_generic.go", m, "List"); !strings.Contains(src, "func ListFilter[L ~[]T, T any](l L, f func(T) bool) L {") || !strings.Contains(src, "func ListMapTo[") {
+		t.Fatal(src)
+	}
+	m.sources[dir+"/fungen_generic.go"] = getMigrateHelpersSource(dir+"/fungen_generic.go", m, "List")
+	if errs := checkMigratedPackage(dir, m); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	m.sources[dir+"/main.go"] = strings.Replace(m.sources[dir+"/main.go"], "stringList(", "(", 1)
+	if errs := checkMigratedPackage(dir, m); len(errs) != 1 || !strings.Contains(errs[0], "Total undefined") {
+		t.Fatal(errs)
+	}
+}
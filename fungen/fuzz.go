@@ -0,0 +1,165 @@
+package fungen
+
+import (
+	"bytes"
+	"text/template"
+)
+
+// fuzzData - the data of the fuzz template: the list type, the type of its members, the name of the fuzz target, the
+// generated names of the methods of the list on its own type, by method name, the traits of the members and which of
+// the helpers of the fuzz target are used by its checks
+type fuzzData struct {
+	List       string
+	Type       string
+	Fuzz       string
+	Methods    map[string]string
+	Numeric    bool
+	Comparable bool
+
+	NeedF     bool
+	NeedPred  bool
+	NeedEqual bool
+}
+
+// fuzzTemplate - the template of the fuzz target of a list type. The members of the list are made from the fuzzed
+// bytes for the numeric types and are the zero value of the type otherwise, and n is the fuzzed count given to Take
+// and Drop. The checks are that Map keeps the length of the list, that PMap gives the same result as Map, that Filter
+// and PFilter only keep the members matching their function and that Filter keeps them in order, and that Take(n) and
+// Drop(n), as well as TakeWhile and DropWhile, put together give the list back
+var fuzzTemplate = template.Must(template.New("fuzz").Option("missingkey=zero").Parse(`
+        func {{.Fuzz}}(f *testing.F) {
+            f.Add([]byte{}, 0)
+            f.Add([]byte{3, 1, 2, 200, 7}, 2)
+            f.Fuzz(func(t *testing.T, data []byte, n int) {
+                l := make({{.List}}, len(data))
+                {{- if .Numeric}}
+                for i, b := range data {
+                    l[i] = {{.Type}}(b)
+                }
+                {{- end}}
+                n = int(uint(n) % uint(len(l)+2))
+                {{- if .NeedF}}
+                fn := func(v {{.Type}}) {{.Type}} { return {{if .Numeric}}v * 2{{else}}v{{end}} }
+                {{- end}}
+                {{- if .NeedPred}}
+                pred := func(v {{.Type}}) bool { return {{if .Numeric}}v > 100{{else}}true{{end}} }
+                {{- end}}
+                {{- if .NeedEqual}}
+                equal := func(a, b {{.List}}) bool {
+                    if len(a) != len(b) {
+                        return false
+                    }
+                    {{- if .Comparable}}
+                    for i := range a {
+                        if a[i] != b[i] {
+                            return false
+                        }
+                    }
+                    {{- end}}
+                    return true
+                }
+                {{- end}}
+                {{with .Methods.Map}}
+                if got := l.{{.}}(fn); len(got) != len(l) {
+                    t.Errorf("{{.}}: got %d members, want %d", len(got), len(l))
+                }
+                {{- end}}
+                {{- with .Methods.PMap}}
+                {{- if $.Methods.Map}}
+                if got, want := l.{{.}}(fn), l.{{$.Methods.Map}}(fn); !equal(got, want) {
+                    t.Errorf("{{.}}: got %v, want the result of {{$.Methods.Map}} %v", got, want)
+                }
+                {{- else}}
+                if got := l.{{.}}(fn); len(got) != len(l) {
+                    t.Errorf("{{.}}: got %d members, want %d", len(got), len(l))
+                }
+                {{- end}}
+                {{- end}}
+                {{- range $name := .FilterMethods}}
+                {
+                    got := l.{{$name}}(pred)
+                    want := 0
+                    for _, v := range l {
+                        if pred(v) {
+                            want++
+                        }
+                    }
+                    if len(got) != want {
+                        t.Errorf("{{$name}}: got %d members, want %d", len(got), want)
+                    }
+                    for _, v := range got {
+                        if !pred(v) {
+                            t.Errorf("{{$name}}: got %v, which does not match the function", v)
+                        }
+                    }
+                    {{- if and $.Comparable (eq $name $.Methods.Filter)}}
+                    i := 0
+                    for _, v := range l {
+                        if i < len(got) && v == got[i] {
+                            i++
+                        }
+                    }
+                    if i != len(got) {
+                        t.Errorf("{{$name}}: got %v, which is not a subsequence of %v", got, l)
+                    }
+                    {{- end}}
+                }
+                {{- end}}
+                {{- if and .Methods.Take .Methods.Drop}}
+                if taken, dropped := l.{{.Methods.Take}}(n), l.{{.Methods.Drop}}(n); !equal(append(append({{.List}}{}, taken...), dropped...), l) {
+                    t.Errorf("{{.Methods.Take}}(%d) and {{.Methods.Drop}}(%d): got %v and %v, want the parts of %v", n, n, taken, dropped, l)
+                }
+                {{- end}}
+                {{- if and .Methods.TakeWhile .Methods.DropWhile}}
+                if taken, dropped := l.{{.Methods.TakeWhile}}(pred), l.{{.Methods.DropWhile}}(pred); !equal(append(append({{.List}}{}, taken...), dropped...), l) {
+                    t.Errorf("{{.Methods.TakeWhile}} and {{.Methods.DropWhile}}: got %v and %v, want the parts of %v", taken, dropped, l)
+                }
+                {{- end}}
+            })
+        }
+        `))
+
+// FilterMethods - the generated names of Filter and PFilter, if they are generated
+func (data fuzzData) FilterMethods() []string {
+	names := []string{}
+	for _, name := range []string{"Filter", "PFilter"} {
+		if data.Methods[name] != "" {
+			names = append(names, data.Methods[name])
+		}
+	}
+	return names
+}
+
+// generateFuzzTarget - generate the fuzz target of the list listName of typeName, checking the invariants of its
+// methods generated by generate on its own type, or an empty string if none of them is generated. The methods
+// overridden by -templates are not checked
+func generateFuzzTarget(typeName, listName string, m map[string]string, methodsMap map[string]bool) string {
+	data := fuzzData{
+		List:       listName,
+		Type:       typeName,
+		Fuzz:       "Fuzz" + getExportedName(listName),
+		Methods:    map[string]string{},
+		Numeric:    isNumericType(typeName, ""),
+		Comparable: isComparableType(typeName, ""),
+	}
+	eachMethod(typeName, m, methodsMap, func(gen Generator, targetType, targetTypeName string) {
+		if _, overridden := methodTemplates[gen.Name]; targetTypeName == "" && !overridden {
+			data.Methods[gen.Name] = getGeneratedMethodName(getStyledName(gen.Name))
+		}
+	})
+
+	has := func(name string) bool { return data.Methods[name] != "" }
+	takeDrop, takeDropWhile := has("Take") && has("Drop"), has("TakeWhile") && has("DropWhile")
+	data.NeedF = has("Map") || has("PMap")
+	data.NeedPred = has("Filter") || has("PFilter") || takeDropWhile
+	data.NeedEqual = has("PMap") && has("Map") || takeDrop || takeDropWhile
+	if !data.NeedF && !data.NeedPred && !data.NeedEqual {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if err := fuzzTemplate.Execute(&buf, data); err != nil {
+		fatalf("fuzz template failed for type '%s': %s", typeName, err)
+	}
+	return "\n" + buf.String() + "\n"
+}
@@ -0,0 +1,863 @@
+package fungen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// testData - the data of the test and example templates: the data of the method templates along with the name of the
+// tested method, as it is generated, the name of the test function, the -full-slice option, whether the members are
+// numeric and whether the list type and its methods are exported
+type testData struct {
+	methodData
+	Method    string
+	Test      string
+	FullSlice bool
+	Numeric   bool
+	Exported  bool
+}
+
+// testCases - the templates shared by the test templates: the empty, single member and several members test cases of
+// a list, whose members are the zero value of the type except for the several members of the numeric types which are
+// distinct and unsorted, and the checks that the result got of Take, Drop and their variants shares the backing array
+// of the list l from its member first unless the results are copies
+const testCases = `{{define "cases"}}
+        var zero {{.Type}}
+        tests := []struct {
+            name string
+            l    {{.List}}
+        }{
+            {"empty", {{.List}}{}},
+            {"single", {{.List}}{zero}},
+            {"several", {{.List}}{ {{- if .Numeric}}3, 1, 2{{else}}zero, zero, zero{{end -}} }},
+        }
+        {{end}}{{define "aliasing"}}{{if .Copy}}
+        if &got[0] == first {
+            t.Errorf("{{.Method}}: the result shares the backing array of the list")
+        }
+        {{else}}
+        if &got[0] != first {
+            t.Errorf("{{.Method}}: the result does not share the backing array of the list")
+        }
+        {{end}}{{if or .Copy .FullSlice}}
+        if cap(got) != len(got) {
+            t.Errorf("{{.Method}}: appending to the result can modify the list")
+        }
+        {{end}}{{end}}`
+
+// parseTestTemplate - parse the test template text of the method name
+func parseTestTemplate(name, text string) *template.Template {
+	funcs := template.FuncMap{"method": getGeneratedMethodName}
+	return template.Must(template.New(name).Funcs(templateFuncs).Funcs(funcs).Option("missingkey=error").Parse(testCases + text))
+}
+
+// testTemplates - the templates of the tests of the built-in methods, by method name
+var testTemplates = map[string]*template.Template{
+	"Map":         parseTestTemplate("Map", mapTestText),
+	"PMap":        parseTestTemplate("PMap", mapTestText),
+	"Filter":      parseTestTemplate("Filter", filterTestText),
+	"PFilter":     parseTestTemplate("PFilter", filterTestText),
+	"Reduce":      parseTestTemplate("Reduce", reduceTestText),
+	"ReduceRight": parseTestTemplate("ReduceRight", reduceTestText),
+	"Take": parseTestTemplate("Take", `
+        func {{.Test}}(t *testing.T) {
+            var zero {{.Type}}
+            tests := []struct {
+                name string
+                l    {{.List}}
+                n    int
+                want int
+            }{
+                {"empty", {{.List}}{}, 1, 0},
+                {"none", {{.List}}{zero, zero}, 0, 0},
+                {"some", {{.List}}{zero, zero, zero}, 2, 2},
+                {"more than the length", {{.List}}{zero}, 2, 1},
+            }
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(tt.n); len(got) != tt.want {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), tt.want)
+                }
+            }
+
+            l := {{.List}}{zero, zero, zero}
+            got := l.{{.Method}}(2)
+            first := &l[0]
+            {{template "aliasing" .}}
+        }
+        `),
+	"TakeWhile": parseTestTemplate("TakeWhile", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(func({{.Type}}) bool { return true }); len(got) != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), len(tt.l))
+                }
+                if got := tt.l.{{.Method}}(func({{.Type}}) bool { return false }); len(got) != 0 {
+                    t.Errorf("{{.Method}} %s: got %d members, want 0", tt.name, len(got))
+                }
+            }
+
+            l := {{.List}}{zero, zero, zero}
+            got := l.{{.Method}}(func({{.Type}}) bool { return true })
+            first := &l[0]
+            {{template "aliasing" .}}
+        }
+        `),
+	"Drop": parseTestTemplate("Drop", `
+        func {{.Test}}(t *testing.T) {
+            var zero {{.Type}}
+            tests := []struct {
+                name string
+                l    {{.List}}
+                n    int
+                want int
+            }{
+                {"empty", {{.List}}{}, 1, 0},
+                {"none", {{.List}}{zero, zero}, 0, 2},
+                {"some", {{.List}}{zero, zero, zero}, 2, 1},
+                {"more than the length", {{.List}}{zero}, 2, 0},
+            }
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(tt.n); len(got) != tt.want {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), tt.want)
+                }
+            }
+
+            l := {{.List}}{zero, zero, zero}
+            got := l.{{.Method}}(1)
+            first := &l[1]
+            {{template "aliasing" .}}
+        }
+        `),
+	"DropWhile": parseTestTemplate("DropWhile", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(func({{.Type}}) bool { return true }); len(got) != 0 {
+                    t.Errorf("{{.Method}} %s: got %d members, want 0", tt.name, len(got))
+                }
+                if got := tt.l.{{.Method}}(func({{.Type}}) bool { return false }); len(got) != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), len(tt.l))
+                }
+            }
+
+            l := {{.List}}{zero, zero, zero}
+            calls := 0
+            got := l.{{.Method}}(func({{.Type}}) bool {
+                calls++
+                return calls == 1
+            })
+            first := &l[1]
+            {{template "aliasing" .}}
+        }
+        `),
+	"Each": parseTestTemplate("Each", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                calls := 0
+                got := tt.l.{{.Method}}(func({{.Type}}) { calls++ })
+                if len(got) != len(tt.l) || calls != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members and %d calls, want %d", tt.name, len(got), calls, len(tt.l))
+                }
+            }
+        }
+        `),
+	"EachI": parseTestTemplate("EachI", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                calls := 0
+                got := tt.l.{{.Method}}(func(i int, _ {{.Type}}) {
+                    if i != calls {
+                        t.Errorf("{{.Method}} %s: got index %d, want %d", tt.name, i, calls)
+                    }
+                    calls++
+                })
+                if len(got) != len(tt.l) || calls != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members and %d calls, want %d", tt.name, len(got), calls, len(tt.l))
+                }
+            }
+        }
+        `),
+	"All": parseTestTemplate("All", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                if !tt.l.{{.Method}}(func({{.Type}}) bool { return true }) {
+                    t.Errorf("{{.Method}} %s: got false, want true", tt.name)
+                }
+                if got := tt.l.{{.Method}}(func({{.Type}}) bool { return false }); got != (len(tt.l) == 0) {
+                    t.Errorf("{{.Method}} %s: got %t, want %t", tt.name, got, len(tt.l) == 0)
+                }
+            }
+        }
+        `),
+	"Any": parseTestTemplate("Any", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(func({{.Type}}) bool { return true }); got != (len(tt.l) > 0) {
+                    t.Errorf("{{.Method}} %s: got %t, want %t", tt.name, got, len(tt.l) > 0)
+                }
+                if tt.l.{{.Method}}(func({{.Type}}) bool { return false }) {
+                    t.Errorf("{{.Method}} %s: got true, want false", tt.name)
+                }
+            }
+        }
+        `),
+	"Contains": parseTestTemplate("Contains", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                want := false
+                for _, v := range tt.l {
+                    want = want || v == zero
+                }
+                if got := tt.l.{{.Method}}(zero); got != want {
+                    t.Errorf("{{.Method}} %s: got %t, want %t", tt.name, got, want)
+                }
+            }
+        }
+        `),
+	"Sort": parseTestTemplate("Sort", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                n := len(tt.l)
+                got := tt.l.{{.Method}}()
+                if len(got) != n {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), n)
+                }
+                for i := 1; i < len(got); i++ {
+                    if got[i] < got[i-1] {
+                        t.Errorf("{{.Method}} %s: the result is not sorted", tt.name)
+                    }
+                }
+            }
+        }
+        `),
+	"Sum": parseTestTemplate("Sum", `
+        func {{.Test}}(t *testing.T) {
+            var zero {{.Type}}
+            tests := []struct {
+                name string
+                l    {{.List}}
+                want {{.Type}}
+            }{
+                {"empty", {{.List}}{}, 0},
+                {"single", {{.List}}{zero}, 0},
+                {"several", {{.List}}{3, 1, 2}, 6},
+            }
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(); got != tt.want {
+                    t.Errorf("{{.Method}} %s: got %v, want %v", tt.name, got, tt.want)
+                }
+            }
+        }
+        `),
+	"Min": parseTestTemplate("Min", minMaxTestText),
+	"Max": parseTestTemplate("Max", minMaxTestText),
+	"FilterMap": parseTestTemplate("FilterMap", `{{if .TargetName}}
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            var u {{.TargetType}}
+            fMap := func({{.Type}}) {{.TargetType}} { return u }
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(fMap); len(got) != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), len(tt.l))
+                }
+                if got := tt.l.{{.Method}}(fMap, func({{.Type}}) bool { return true }); len(got) != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), len(tt.l))
+                }
+                if got := tt.l.{{.Method}}(fMap, func({{.Type}}) bool { return true }, func({{.Type}}) bool { return false }); len(got) != 0 {
+                    t.Errorf("{{.Method}} %s: got %d members, want 0", tt.name, len(got))
+                }
+            }
+        }
+        {{end}}`),
+	"FilterMapTo": parseTestTemplate("FilterMapTo", `{{if .TargetName}}
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            var u {{.TargetType}}
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(func({{.Type}}) ({{.TargetType}}, bool) { return u, true }); len(got) != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), len(tt.l))
+                }
+                if got := tt.l.{{.Method}}(func({{.Type}}) ({{.TargetType}}, bool) { return u, false }); len(got) != 0 {
+                    t.Errorf("{{.Method}} %s: got %d members, want 0", tt.name, len(got))
+                }
+            }
+        }
+        {{end}}`),
+	"MapNotNil": parseTestTemplate("MapNotNil", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            var u {{.TargetType}}
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(func({{.Type}}) *{{.TargetType}} { return &u }); len(got) != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), len(tt.l))
+                }
+                if got := tt.l.{{.Method}}(func({{.Type}}) *{{.TargetType}} { return nil }); len(got) != 0 {
+                    t.Errorf("{{.Method}} %s: got %d members, want 0", tt.name, len(got))
+                }
+            }
+        }
+        `),
+	"FlatMap": parseTestTemplate("FlatMap", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            var u {{.TargetType}}
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(func({{.Type}}) {{.TargetList}} { return {{.TargetList}}{u, u} }); len(got) != 2*len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), 2*len(tt.l))
+                }
+            }
+        }
+        `),
+	"Fold": parseTestTemplate("Fold", `{{if .TargetName}}
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            var u {{.TargetType}}
+            for _, tt := range tests {
+                calls := 0
+                tt.l.{{.Method}}(u, func(acc {{.TargetType}}, _ {{.Type}}) {{.TargetType}} {
+                    calls++
+                    return acc
+                })
+                if calls != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d calls, want %d", tt.name, calls, len(tt.l))
+                }
+            }
+        }
+        {{end}}`),
+	"Zip": parseTestTemplate("Zip", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            var u {{.TargetType}}
+            other := {{.TargetList}}{u, u}
+            for _, tt := range tests {
+                want := len(tt.l)
+                if want > len(other) {
+                    want = len(other)
+                }
+                if got := tt.l.{{.Method}}(other, func(v {{.Type}}, _ {{.TargetType}}) {{.Type}} { return v }); len(got) != want {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), want)
+                }
+            }
+        }
+        `),
+	"GroupBy": parseTestTemplate("GroupBy", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            var u {{.TargetType}}
+            for _, tt := range tests {
+                got := tt.l.{{.Method}}(func({{.Type}}) {{.TargetType}} { return u })
+                if len(tt.l) > 0 && (len(got) != 1 || len(got[u]) != len(tt.l)) || len(tt.l) == 0 && len(got) != 0 {
+                    t.Errorf("{{.Method}} %s: got %d groups, want all the members in one group", tt.name, len(got))
+                }
+            }
+        }
+        `),
+	"CountBy": parseTestTemplate("CountBy", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            var u {{.TargetType}}
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(func({{.Type}}) {{.TargetType}} { return u }); got[u] != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d, want %d", tt.name, got[u], len(tt.l))
+                }
+            }
+        }
+        `),
+	"SumBy": parseTestTemplate("SumBy", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                if got, want := tt.l.{{.Method}}(func({{.Type}}) {{.TargetType}} { return 1 }), {{.TargetType}}(len(tt.l)); got != want {
+                    t.Errorf("{{.Method}} %s: got %v, want %v", tt.name, got, want)
+                }
+            }
+        }
+        `),
+	"MinBy": parseTestTemplate("MinBy", byTestText),
+	"MaxBy": parseTestTemplate("MaxBy", byTestText),
+	"By": parseTestTemplate("By", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                by := tt.l.{{.Method}}(func(a, b {{.Type}}) bool { return false })
+                sort.Sort(by)
+                if by.Len() != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, by.Len(), len(tt.l))
+                }
+            }
+        }
+        `),
+	"MapInPlace": parseTestTemplate("MapInPlace", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                calls := 0
+                got := tt.l.{{.Method}}(func(v {{.Type}}) {{.Type}} {
+                    calls++
+                    return v
+                })
+                if len(got) != len(tt.l) || calls != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members and %d calls, want %d", tt.name, len(got), calls, len(tt.l))
+                }
+            }
+        }
+        `),
+	"FilterInPlace": parseTestTemplate("FilterInPlace", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                n := len(tt.l)
+                if got := tt.l.{{.Method}}(func({{.Type}}) bool { return true }); len(got) != n {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), n)
+                }
+                if got := tt.l.{{.Method}}(func({{.Type}}) bool { return false }); len(got) != 0 {
+                    t.Errorf("{{.Method}} %s: got %d members, want 0", tt.name, len(got))
+                }
+            }
+        }
+        `),
+	"CompactNil": parseTestTemplate("CompactNil", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(); len(got) != 0 {
+                    t.Errorf("{{.Method}} %s: got %d members, want 0", tt.name, len(got))
+                }
+            }
+        }
+        `),
+	"Grow": parseTestTemplate("Grow", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(5); len(got) != len(tt.l) || cap(got)-len(got) < 5 {
+                    t.Errorf("{{.Method}} %s: got %d members and a capacity of %d, want %d members and room for 5 more", tt.name, len(got), cap(got), len(tt.l))
+                }
+            }
+        }
+        `),
+	"Clip": parseTestTemplate("Clip", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                l := make({{.List}}, len(tt.l), len(tt.l)+5)
+                copy(l, tt.l)
+                if got := l.{{.Method}}(); len(got) != len(tt.l) || cap(got) != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members and a capacity of %d, want %d", tt.name, len(got), cap(got), len(tt.l))
+                }
+            }
+        }
+        `),
+	"Truncate": parseTestTemplate("Truncate", `
+        func {{.Test}}(t *testing.T) {
+            var zero {{.Type}}
+            tests := []struct {
+                name string
+                l    {{.List}}
+                n    int
+                want int
+            }{
+                {"empty", {{.List}}{}, 1, 0},
+                {"none", {{.List}}{zero, zero}, 0, 0},
+                {"some", {{.List}}{zero, zero, zero}, 2, 2},
+                {"more than the length", {{.List}}{zero}, 2, 1},
+            }
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(tt.n); len(got) != tt.want || cap(got) != cap(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members and a capacity of %d, want %d and %d", tt.name, len(got), cap(got), tt.want, cap(tt.l))
+                }
+            }
+        }
+        `),
+	"Heap": parseTestTemplate("Heap", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                h := tt.l.{{.Method}}(func(a, b {{.Type}}) bool { return false })
+                heap.Init(h)
+                heap.Push(h, zero)
+                if h.Len() != len(tt.l)+1 {
+                    t.Errorf("{{.Method}} %s: got %d members after Push, want %d", tt.name, h.Len(), len(tt.l)+1)
+                }
+                heap.Pop(h)
+                if len(h.List()) != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members after Pop, want %d", tt.name, len(h.List()), len(tt.l))
+                }
+            }
+        }
+        `),
+	"String": parseTestTemplate("String", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                if got := tt.l.String(); len(got) < 2 || got[0] != '[' || got[len(got)-1] != ']' || len(tt.l) == 0 && got != "[]" {
+                    t.Errorf("String %s: got %q", tt.name, got)
+                }
+            }
+        }
+        `),
+	"SQL": parseTestTemplate("SQL", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                value, err := tt.l.Value()
+                if err != nil {
+                    t.Fatalf("Value %s: %s", tt.name, err)
+                }
+                var got {{.List}}
+                if err := got.Scan(value); err != nil {
+                    t.Fatalf("Scan %s: %s", tt.name, err)
+                }
+                if len(got) != len(tt.l) {
+                    t.Errorf("Scan %s: got %d members, want %d", tt.name, len(got), len(tt.l))
+                }
+            }
+
+            var l {{.List}}
+            if value, err := l.Value(); value != nil || err != nil {
+                t.Errorf("Value: got %v and %v for a nil list, want nil", value, err)
+            }
+        }
+        `),
+	"JSON": parseTestTemplate("JSON", `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                b, err := tt.l.{{method "ToJSON"}}()
+                if err != nil {
+                    t.Fatalf("ToJSON %s: %s", tt.name, err)
+                }
+                got, err := {{funcName "From" .List "JSON"}}(b)
+                if err != nil {
+                    t.Fatalf("{{funcName "From" .List "JSON"}} %s: %s", tt.name, err)
+                }
+                if len(got) != len(tt.l) {
+                    t.Errorf("{{funcName "From" .List "JSON"}} %s: got %d members, want %d", tt.name, len(got), len(tt.l))
+                }
+            }
+        }
+        `),
+	"Builder": parseTestTemplate("Builder", `
+        func {{.Test}}(t *testing.T) {
+            var zero {{.Type}}
+            l := {{constructorName (print .List "Builder")}}(0).Add(zero).AddIf(false, zero).AddIf(true, zero).AddAll(zero, zero).Build()
+            if len(l) != 4 {
+                t.Errorf("Build: got %d members, want 4", len(l))
+            }
+            if cap(l) != len(l) {
+                t.Errorf("Build: appending to the result can modify the builder")
+            }
+        }
+        `),
+	"SafeList": parseTestTemplate("SafeList", `
+        func {{.Test}}(t *testing.T) {
+            var zero {{.Type}}
+            var s {{wrapperName .List "SafeList"}}
+            var wg sync.WaitGroup
+            for i := 0; i < 10; i++ {
+                wg.Add(1)
+                go func() {
+                    defer wg.Done()
+                    s.Append(zero, zero)
+                }()
+            }
+            wg.Wait()
+
+            if got := s.Snapshot(); len(got) != 20 {
+                t.Errorf("Snapshot: got %d members, want 20", len(got))
+            }
+            if got := s.Filter(func({{.Type}}) bool { return true }); len(got) != 20 {
+                t.Errorf("Filter: got %d members, want 20", len(got))
+            }
+            calls := 0
+            s.Each(func({{.Type}}) { calls++ })
+            if calls != 20 {
+                t.Errorf("Each: got %d calls, want 20", calls)
+            }
+        }
+        `),
+	"Vector": parseTestTemplate("Vector", `
+        func {{.Test}}(t *testing.T) {
+            var zero {{.Type}}
+            v := {{constructorName (wrapperName .List "Vector")}}(zero, zero)
+            if v2 := v.Append(zero); v2.Len() != 3 || v.Len() != 2 {
+                t.Errorf("Append: got %d members and %d in the original vector, want 3 and 2", v2.Len(), v.Len())
+            }
+            if got := v.Map(func(u {{.Type}}) {{.Type}} { return u }); got.Len() != 2 {
+                t.Errorf("Map: got %d members, want 2", got.Len())
+            }
+            if got := v.Filter(func({{.Type}}) bool { return false }); got.Len() != 0 {
+                t.Errorf("Filter: got %d members, want 0", got.Len())
+            }
+            if got := v.Take(1); got.Len() != 1 {
+                t.Errorf("Take: got %d members, want 1", got.Len())
+            }
+            if got := v.Drop(1); got.Len() != 1 {
+                t.Errorf("Drop: got %d members, want 1", got.Len())
+            }
+            if got := v.ToList(); len(got) != 2 {
+                t.Errorf("ToList: got %d members, want 2", len(got))
+            }
+        }
+        `),
+	"Pipeline": parseTestTemplate("Pipeline", `
+        func {{.Test}}(t *testing.T) {
+            var zero {{.Type}}
+            l := make({{.List}}, 50)
+            for i := range l {
+                l[i] = zero
+            }
+            i := 0
+            got, err := {{constructorName (wrapperName .List "Pipeline")}}(l).Map(func(u {{.Type}}) {{.Type}} { return u }).Filter(func({{.Type}}) bool { return true }).Workers(4).Buffer(2).Run()
+            if err != nil || len(got) != 50 {
+                t.Errorf("Run: got %d members and error %v, want 50 and no error", len(got), err)
+            }
+            got, err = {{constructorName (wrapperName .List "Pipeline")}}(l).Filter(func({{.Type}}) bool { i++; return i%2 == 0 }).Run()
+            if err != nil || len(got) != 25 {
+                t.Errorf("Filter: got %d members and error %v, want 25 and no error", len(got), err)
+            }
+            ctx, cancel := context.WithCancel(context.Background())
+            cancel()
+            if _, err := {{constructorName (wrapperName .List "Pipeline")}}(l).Workers(4).RunCtx(ctx); err != context.Canceled {
+                t.Errorf("RunCtx: got error %v, want %v", err, context.Canceled)
+            }
+            wantErr := fmt.Errorf("stage error")
+            _, err = {{constructorName (wrapperName .List "Pipeline")}}(l).MapCtx(context.Background(), func(context.Context, {{.Type}}) ({{.Type}}, error) { return zero, wantErr }).Workers(4).Run()
+            if err != wantErr {
+                t.Errorf("MapCtx: got error %v, want %v", err, wantErr)
+            }
+            _, err = {{constructorName (wrapperName .List "Pipeline")}}(l).FilterCtx(ctx, func(context.Context, {{.Type}}) (bool, error) { return true, nil }).Run()
+            if err != context.Canceled {
+                t.Errorf("FilterCtx: got error %v, want %v", err, context.Canceled)
+            }
+        }
+        `),
+}
+
+// mapTestText - the test template of Map and PMap, which also checks that PMap keeps the order of the members
+const mapTestText = `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                var u {{.TargetType}}
+                got := tt.l.{{.Method}}(func({{.Type}}) {{.TargetType}} { return u })
+                if len(got) != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), len(tt.l))
+                }
+            }
+            {{if and .Numeric (not .TargetName)}}
+            l := make({{.List}}, 100)
+            for i := range l {
+                l[i] = {{.Type}}(i)
+            }
+            got := l.{{.Method}}(func(v {{.Type}}) {{.Type}} { return v })
+            for i := range l {
+                if got[i] != l[i] {
+                    t.Errorf("{{.Method}}: got %v at index %d, want %v", got[i], i, l[i])
+                    break
+                }
+            }
+            {{end}}
+        }
+        `
+
+// filterTestText - the test template of Filter and PFilter
+const filterTestText = `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                if got := tt.l.{{.Method}}(func({{.Type}}) bool { return true }); len(got) != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d members, want %d", tt.name, len(got), len(tt.l))
+                }
+                if got := tt.l.{{.Method}}(func({{.Type}}) bool { return false }); len(got) != 0 {
+                    t.Errorf("{{.Method}} %s: got %d members, want 0", tt.name, len(got))
+                }
+            }
+        }
+        `
+
+// reduceTestText - the test template of Reduce and ReduceRight
+const reduceTestText = `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            for _, tt := range tests {
+                calls := 0
+                tt.l.{{.Method}}(zero, func(acc, _ {{.Type}}) {{.Type}} {
+                    calls++
+                    return acc
+                })
+                if calls != len(tt.l) {
+                    t.Errorf("{{.Method}} %s: got %d calls, want %d", tt.name, calls, len(tt.l))
+                }
+            }
+        }
+        `
+
+// minMaxTestText - the test template of Min and Max
+const minMaxTestText = `
+        func {{.Test}}(t *testing.T) {
+            var zero {{.Type}}
+            tests := []struct {
+                name string
+                l    {{.List}}
+                want {{.Type}}
+                ok   bool
+            }{
+                {"empty", {{.List}}{}, zero, false},
+                {"single", {{.List}}{zero}, zero, true},
+                {{if .Numeric}}{"several", {{.List}}{3, 1, 2}, {{if eq .Method "Min" "min"}}1{{else}}3{{end}}, true},{{end}}
+            }
+            for _, tt := range tests {
+                if got, ok := tt.l.{{.Method}}(); got != tt.want || ok != tt.ok {
+                    t.Errorf("{{.Method}} %s: got %v and %t, want %v and %t", tt.name, got, ok, tt.want, tt.ok)
+                }
+            }
+        }
+        `
+
+// byTestText - the test template of MinBy and MaxBy
+const byTestText = `
+        func {{.Test}}(t *testing.T) {
+            {{template "cases" .}}
+            var u {{.TargetType}}
+            for _, tt := range tests {
+                if _, ok := tt.l.{{.Method}}(func({{.Type}}) {{.TargetType}} { return u }); ok != (len(tt.l) > 0) {
+                    t.Errorf("{{.Method}} %s: got %t, want %t", tt.name, ok, len(tt.l) > 0)
+                }
+            }
+        }
+        `
+
+// getGeneratedMethodName - get the name of the method name as it is generated on the list types, which is unexported
+// with -unexported unless it implements a standard interface
+func getGeneratedMethodName(name string) string {
+	if *unexported && !interfaceMethods[name] {
+		return getUnexportedName(name)
+	}
+	return name
+}
+
+// generateTests - generate the tests of the methods of the list listName of typeName, as generated by generate, from
+// the templates by method name, which are the test or the example templates. The methods overridden by -templates and
+// the methods without a template are not tested
+func generateTests(templates map[string]*template.Template, typeName, listName string, m map[string]string, methodsMap map[string]bool) string {
+	var code strings.Builder
+	eachMethod(typeName, m, methodsMap, func(gen Generator, targetType, targetTypeName string) {
+		tmpl, ok := templates[gen.Name]
+		if _, overridden := methodTemplates[gen.Name]; !ok || overridden {
+			return
+		}
+
+		data := testData{
+			methodData: getMethodData(listName, typeName, targetType, targetTypeName),
+			FullSlice:  *fullSlice,
+			Numeric:    isNumericType(typeName, ""),
+			Exported:   ast.IsExported(listName) && !*unexported,
+		}
+		name := getStyledName(gen.Name) + data.TargetName
+		data.Method = getGeneratedMethodName(name)
+		data.Test = "Test" + getExportedName(listName) + name
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			fatalf("test template '%s' failed for type '%s': %s", tmpl.Name(), typeName, err)
+		}
+		if strings.TrimSpace(buf.String()) != "" {
+			code.WriteString("\n" + buf.String() + "\n")
+		}
+	})
+	return code.String()
+}
+
+// listTestsGenerator - a function generating the tests of the methods of the list listName of typeName
+type listTestsGenerator func(typeName, listName string, m map[string]string, methodsMap map[string]bool) string
+
+// withTemplates - get the function generating the tests of a list from the test or the example templates
+func withTemplates(templates map[string]*template.Template) listTestsGenerator {
+	return func(typeName, listName string, m map[string]string, methodsMap map[string]bool) string {
+		return generateTests(templates, typeName, listName, m, methodsMap)
+	}
+}
+
+// generateTestSource - generate the source of the test file of the generated file with the types typeKeys and the
+// pairs pairList, with the tests of each list generated by generateList, or an empty string if there is nothing to
+// test
+func generateTestSource(generateList listTestsGenerator, typeKeys []string, pairList [][2]string, typeMap map[string]string, typeMethodsMaps map[string]map[string]bool, typeNames []string) string {
+	var code strings.Builder
+	for _, k := range typeKeys {
+		code.WriteString(generateList(k, getListName(k, typeMap), typeMap, typeMethodsMaps[k]))
+	}
+	methodsMap := getMethodsMap(*methods)
+	for _, pair := range pairList {
+		pairName := getPairName(pair[0], pair[1], typeMap)
+		pairMap := getPairMap(pairName, typeMap)
+		code.WriteString(generateList(pairName, getListName(pairName, pairMap), pairMap, methodsMap))
+	}
+	if code.Len() == 0 {
+		return ""
+	}
+
+	src := fmt.Sprintf(`%[3]s%[2]s// Package %[1]s - `+generatedMarker+`%[4]s
+            package %[1]s
+            `, *packageName, getBuildConstraint(*buildTags), getFileHeader(*headerFile), getProvenance())
+
+	known := getKnownImports(typeNames)
+	for _, importPath := range []string{"container/heap", "fmt", "sort", "testing", "testing/quick"} {
+		known[path.Base(importPath)] = strconv.Quote(importPath)
+	}
+	return getImportsSource(f(src+code.String()), known)
+}
+
+// testFileKind - a kind of test file generated along with each generated file, named with suffix, with the tests of
+// each list generated by generateList
+type testFileKind struct {
+	suffix       string
+	generateList listTestsGenerator
+}
+
+// getTestFileKinds - get the kinds of test files to generate according to -gen-tests, -gen-examples, -gen-fuzz and
+// -gen-props
+func getTestFileKinds() []testFileKind {
+	kinds := []testFileKind{}
+	if *genTests {
+		kinds = append(kinds, testFileKind{"_test.go", withTemplates(testTemplates)})
+	}
+	if *genExamples {
+		kinds = append(kinds, testFileKind{"_example_test.go", withTemplates(exampleTemplates)})
+	}
+	if *genFuzz {
+		kinds = append(kinds, testFileKind{"_fuzz_test.go", generateFuzzTarget})
+	}
+	if *genProps {
+		kinds = append(kinds, testFileKind{"_prop_test.go", generatePropertyTests})
+	}
+	return kinds
+}
+
+// addTestFiles - add the test files of the generated file fileName, with the types typeKeys and the pairs pairList, to
+// files according to getTestFileKinds. The test files with nothing to test are not added
+func addTestFiles(files map[string]string, fileName string, typeKeys []string, pairList [][2]string, typeMap map[string]string, typeMethodsMaps map[string]map[string]bool, typeNames []string) {
+	for _, testFile := range getTestFileKinds() {
+		if src := generateTestSource(testFile.generateList, typeKeys, pairList, typeMap, typeMethodsMaps, typeNames); src != "" {
+			files[getTestFileName(fileName, testFile.suffix)] = src
+		}
+	}
+}
+
+// getTestFileName - get the name of the test file of the generated file fileName with the suffix, eg.
+// 'fungen_auto_test.go' for '_test.go'
+func getTestFileName(fileName, suffix string) string {
+	return strings.TrimSuffix(fileName, ".go") + suffix
+}
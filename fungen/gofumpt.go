@@ -0,0 +1,182 @@
+package fungen
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// commentDirective - the comments which are read by tools and must not be separated from their slashes by a space,
+// as gofumpt leaves them
+var commentDirective = regexp.MustCompile(`^//([a-z0-9]+:[a-z0-9]|line |extern |export |sys|nolint|#|\+build|/)`)
+
+// getGofumptSource - format src with the rules of gofumpt which apply to the generated code, so that it is left as is
+// by gofumpt: no empty lines at the start and the end of the function bodies, of the blocks of a lone statement, of the
+// composite literals and of the field lists, no empty lines before a simple error check, the standard imports in their
+// own group at the top, short variable declarations instead of the simple var declarations in functions and a space
+// after the slashes of the comments which are not directives
+func getGofumptSource(src string) string {
+	fset, file := parseSource(src)
+	tokFile := fset.File(file.Pos())
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+	line := func(pos token.Pos) int {
+		return fset.Position(pos).Line
+	}
+	lineText := func(l int) string {
+		end := len(src)
+		if l < tokFile.LineCount() {
+			end = offset(tokFile.LineStart(l + 1))
+		}
+		return src[offset(tokFile.LineStart(l)):end]
+	}
+
+	// removed - the empty lines to remove, by line number
+	removed := map[int]bool{}
+	removeEmptyLines := func(from, to int, fromStart bool) {
+		if fromStart {
+			for l := from + 1; l < to && strings.TrimSpace(lineText(l)) == ""; l++ {
+				removed[l] = true
+			}
+		}
+		for l := to - 1; l > from && strings.TrimSpace(lineText(l)) == ""; l-- {
+			removed[l] = true
+		}
+	}
+	trimBraces := func(opening, closing token.Pos) {
+		if opening.IsValid() && closing.IsValid() {
+			removeEmptyLines(line(opening), line(closing), true)
+		}
+	}
+	removeBeforeErrCheck := func(list []ast.Stmt) {
+		for i := 1; i < len(list); i++ {
+			if isErrCheck(list[i]) && assignsErr(list[i-1]) {
+				removeEmptyLines(line(list[i-1].End()), line(list[i].Pos()), false)
+			}
+		}
+	}
+
+	edits := []edit{}
+	var parents []ast.Node
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			parents = parents[:len(parents)-1]
+			return false
+		}
+		var parent ast.Node
+		if len(parents) > 0 {
+			parent = parents[len(parents)-1]
+		}
+		parents = append(parents, n)
+
+		switch node := n.(type) {
+		case *ast.BlockStmt:
+			_, isFuncDecl := parent.(*ast.FuncDecl)
+			_, isFuncLit := parent.(*ast.FuncLit)
+			if isFuncDecl || isFuncLit || len(node.List) <= 1 {
+				trimBraces(node.Lbrace, node.Rbrace)
+			}
+			removeBeforeErrCheck(node.List)
+		case *ast.CaseClause:
+			removeBeforeErrCheck(node.Body)
+		case *ast.CompositeLit:
+			trimBraces(node.Lbrace, node.Rbrace)
+		case *ast.FieldList:
+			trimBraces(node.Opening, node.Closing)
+		case *ast.DeclStmt:
+			genDecl := node.Decl.(*ast.GenDecl)
+			if genDecl.Tok != token.VAR || genDecl.Lparen.IsValid() || len(genDecl.Specs) != 1 {
+				break
+			}
+			spec := genDecl.Specs[0].(*ast.ValueSpec)
+			if len(spec.Names) == 1 && spec.Names[0].Name != "_" && spec.Type == nil && len(spec.Values) == 1 {
+				edits = append(edits, edit{offset(genDecl.Pos()), offset(spec.Values[0].Pos()), spec.Names[0].Name + " := "})
+			}
+		}
+		return true
+	})
+
+	grouped := []ast.Node{}
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT || !genDecl.Lparen.IsValid() {
+			continue
+		}
+		std, other := []string{}, []string{}
+		for _, spec := range genDecl.Specs {
+			spec := spec.(*ast.ImportSpec)
+			text := src[offset(spec.Pos()):offset(spec.End())]
+			if importPath := strings.Trim(spec.Path.Value, "`\""); strings.Contains(strings.Split(importPath, "/")[0], ".") {
+				other = append(other, text)
+			} else {
+				std = append(std, text)
+			}
+		}
+		if len(std) > 0 && len(other) > 0 {
+			imports := "import (\n" + strings.Join(std, "\n") + "\n\n" + strings.Join(other, "\n") + "\n)"
+			edits = append(edits, edit{offset(genDecl.Pos()), offset(genDecl.End()), imports})
+			grouped = append(grouped, genDecl)
+		}
+	}
+
+	// the comments of the grouped import declarations are dropped along with them
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			text := comment.Text
+			if isInDecls(comment, grouped) || !strings.HasPrefix(text, "//") || len(text) == 2 || text[2] == ' ' || text[2] == '\t' || commentDirective.MatchString(text) {
+				continue
+			}
+			edits = append(edits, edit{offset(comment.Pos()) + 2, offset(comment.Pos()) + 2, " "})
+		}
+	}
+
+	for l := range removed {
+		end := len(src)
+		if l < tokFile.LineCount() {
+			end = offset(tokFile.LineStart(l + 1))
+		}
+		edits = append(edits, edit{offset(tokFile.LineStart(l)), end, ""})
+	}
+	return f(applyEdits(src, edits))
+}
+
+// isInDecls - whether node is within one of decls
+func isInDecls(node ast.Node, decls []ast.Node) bool {
+	for _, decl := range decls {
+		if node.Pos() >= decl.Pos() && node.End() <= decl.End() {
+			return true
+		}
+	}
+	return false
+}
+
+// isErrCheck - whether stmt is a simple error check, ie. 'if err != nil {' without an init statement
+func isErrCheck(stmt ast.Stmt) bool {
+	ifStmt, ok := stmt.(*ast.IfStmt)
+	if !ok || ifStmt.Init != nil {
+		return false
+	}
+	cond, ok := ifStmt.Cond.(*ast.BinaryExpr)
+	if !ok || cond.Op != token.NEQ {
+		return false
+	}
+	x, xOk := cond.X.(*ast.Ident)
+	y, yOk := cond.Y.(*ast.Ident)
+	return xOk && yOk && x.Name == "err" && y.Name == "nil"
+}
+
+// assignsErr - whether stmt is an assignment to err
+func assignsErr(stmt ast.Stmt) bool {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok {
+		return false
+	}
+	for _, lhs := range assign.Lhs {
+		if ident, ok := lhs.(*ast.Ident); ok && ident.Name == "err" {
+			return true
+		}
+	}
+	return false
+}
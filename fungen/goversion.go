@@ -0,0 +1,55 @@
+package fungen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// anyMinor - the minor version of Go from which the generated code uses the any alias instead of interface{}
+	anyMinor = 18
+	// slicesMinor - the minor version of Go from which the generated code delegates to the slices package
+	slicesMinor = 21
+	// iterMinor - the minor version of Go from which the iter.Seq adapters are generated
+	iterMinor = 23
+)
+
+// goVersionPattern - the pattern of the versions of -go, eg. '1.21', 'go1.21' or '1.21.3'
+var goVersionPattern = regexp.MustCompile(`^(?:go)?1\.(\d+)(?:\.\d+)?$`)
+
+// getGoMinor - get the minor version of the Go version of -go, or 0 if it is empty, in which case the generated code
+// builds with all the toolchains
+func getGoMinor(version string) (int, error) {
+	if version == "" {
+		return 0, nil
+	}
+	match := goVersionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return 0, fmt.Errorf("the Go version '%s' is not valid, eg. '1.21'", version)
+	}
+	return strconv.Atoi(match[1])
+}
+
+// isGoAtLeast - whether the Go version of -go is at least 1.minor
+func isGoAtLeast(minor int) bool {
+	goMinor, _ := getGoMinor(*goVersion)
+	return goMinor >= minor
+}
+
+// getGoImports - get the import paths of the packages which the idioms selected by -go may use
+func getGoImports() []string {
+	importPaths := []string{}
+	if isGoAtLeast(slicesMinor) {
+		importPaths = append(importPaths, "slices")
+	}
+	if isGoAtLeast(iterMinor) {
+		importPaths = append(importPaths, "iter")
+	}
+	return importPaths
+}
+
+// supportsIter - whether the iter.Seq adapters can be generated, which needs -go 1.23 or later
+func supportsIter(typeName, targetType string) bool {
+	return isGoAtLeast(iterMinor)
+}
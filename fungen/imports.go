@@ -0,0 +1,175 @@
+package fungen
+
+import (
+	"go/importer"
+	"go/token"
+	gotypes "go/types"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// compiledImporter - the importer of the packages from their compiled export data, such as the standard packages
+	compiledImporter = importer.Default()
+	// sourceImporter - the importer of the packages from their sources, found from the directory of the target package
+	// as the go command does, which finds the packages of its module graph, including the vendored and replaced modules
+	sourceImporter = importer.ForCompiler(token.NewFileSet(), "source", nil).(gotypes.ImporterFrom)
+	// fileImportPaths - the import paths of the packages imported by the files of the target package, by package name,
+	// loaded once when first needed
+	fileImportPaths map[string][]string
+	// dependencyImportPaths - the import paths of the dependencies of the target package, by package name, listed once
+	// when first needed
+	dependencyImportPaths map[string][]string
+	// standardImportPaths - the import paths of the standard packages, by package name, listed once when first needed
+	standardImportPaths map[string][]string
+	// moduleImportPaths - the import paths of the packages of the modules required by the module of the target package,
+	// by package name, listed once when first needed
+	moduleImportPaths map[string][]string
+)
+
+// resolveImportPath - get the import path of the package named name, used by a qualified type which is not given in
+// -import: the path under which the target package imports it or, failing that, the path of the package of that name
+// among the dependencies of the target package or, failing that, among the standard packages and the packages of the
+// modules required by its module, as listed by go list, which follows the module graph, including the vendored and
+// replaced modules. A standard package is preferred to the other dependencies of the same name, but not to the packages
+// of the required modules, which the package may be about to import. It is an error if the package cannot be found
+func resolveImportPath(name string) string {
+	if fileImportPaths == nil {
+		fileImportPaths = getFileImportPaths()
+	}
+	importPaths, ok := fileImportPaths[name]
+	if !ok {
+		if dependencyImportPaths == nil {
+			dependencyImportPaths = listImportPaths("-deps", ".")
+		}
+		importPaths, ok = dependencyImportPaths[name]
+	}
+	if !ok {
+		if standardImportPaths == nil {
+			standardImportPaths, moduleImportPaths = listImportPaths("std"), getModuleImportPaths()
+		}
+		importPaths = append(append([]string{}, standardImportPaths[name]...), moduleImportPaths[name]...)
+	}
+
+	switch {
+	case len(importPaths) == 0:
+		fatalf("cannot find the package '%s' of a qualified type among the imports and the modules of the package: give its import path with -import %s=path", name, name)
+	case len(importPaths) > 1:
+		for _, importPath := range importPaths {
+			if importPath == name && ok {
+				return name
+			}
+		}
+		fatalf("the package '%s' of a qualified type is ambiguous, it can be any of %s: give its import path with -import %s=path", name, strings.Join(importPaths, ", "), name)
+	}
+	return importPaths[0]
+}
+
+// addImportPath - add importPath to the import paths of the package named name in importPaths, once
+func addImportPath(importPaths map[string][]string, name, importPath string) {
+	for _, p := range importPaths[name] {
+		if p == importPath {
+			return
+		}
+	}
+	importPaths[name] = append(importPaths[name], importPath)
+}
+
+// getFileImportPaths - get the import paths of the packages imported by the files of the target package, by the name
+// they are imported with
+func getFileImportPaths() map[string][]string {
+	importPaths := map[string][]string{}
+	loadPackageFiles()
+	for _, file := range packageFiles {
+		for _, spec := range file.Imports {
+			name := getImportName(spec)
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			if name != "_" && name != "." {
+				addImportPath(importPaths, name, importPath)
+			}
+		}
+	}
+	return importPaths
+}
+
+// listImportPaths - get the import paths of the packages listed by go list with the arguments args, from the directory of
+// the target package, by package name. The vendored packages are given by the path they are imported with, and the
+// internal packages of the standard library, which cannot be imported, are left out. It is empty if go list fails, eg.
+// when the go command is not installed
+func listImportPaths(args ...string) map[string][]string {
+	importPaths := map[string][]string{}
+	listArgs := []string{"list", "-e", "-f", "{{.Name}} {{.ImportPath}} {{.Standard}}"}
+	if tags := getVetTags(*buildTags); len(tags) > 0 {
+		listArgs = append(listArgs, "-tags", strings.Join(tags, ","))
+	}
+	cmd := exec.Command("go", append(listArgs, args...)...)
+	cmd.Dir = getOutputDir()
+	output, err := cmd.Output()
+	if err != nil {
+		verbosef("cannot list the packages %s to resolve the packages of the qualified types: %s", strings.Join(args, " "), err)
+		return importPaths
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[0] == "main" {
+			continue
+		}
+		name, importPath, standard := fields[0], fields[1], fields[2] == "true"
+		if standard && (strings.HasPrefix(importPath, "vendor/") || strings.HasPrefix(importPath, "internal/") || strings.Contains(importPath, "/internal/")) {
+			continue
+		}
+		if i := strings.LastIndex(importPath, "/vendor/"); i >= 0 {
+			importPath = importPath[i+len("/vendor/"):]
+		}
+		addImportPath(importPaths, name, importPath)
+	}
+	for _, paths := range importPaths {
+		sort.Strings(paths)
+	}
+	return importPaths
+}
+
+// getModuleImportPaths - get the import paths of the packages of the modules required by the module of the target
+// package, as listed by go list -m all, including the modules which are required but not imported yet. It is empty if
+// the target package is not in a module
+func getModuleImportPaths() map[string][]string {
+	cmd := exec.Command("go", "list", "-m", "-f", "{{if not .Main}}{{.Path}}{{end}}", "all")
+	cmd.Dir = getOutputDir()
+	output, err := cmd.Output()
+	if err != nil {
+		verbosef("cannot list the modules of the package to resolve the packages of the qualified types: %s", err)
+		return map[string][]string{}
+	}
+	patterns := []string{}
+	for _, modulePath := range strings.Fields(string(output)) {
+		patterns = append(patterns, modulePath+"/...")
+	}
+	if len(patterns) == 0 {
+		return map[string][]string{}
+	}
+	return listImportPaths(patterns...)
+}
+
+// packageImporter - the importer of the type checks of the target package, which imports the packages from their
+// compiled export data or, failing that, from their sources
+type packageImporter struct{}
+
+// Import - import the package of the import path importPath
+func (packageImporter) Import(importPath string) (*gotypes.Package, error) {
+	pkg, err := compiledImporter.Import(importPath)
+	if err == nil {
+		return pkg, nil
+	}
+	dir, absErr := filepath.Abs(getOutputDir())
+	if absErr != nil {
+		return nil, err
+	}
+	if pkg, srcErr := sourceImporter.ImportFrom(importPath, dir, 0); srcErr == nil {
+		return pkg, nil
+	}
+	return nil, err
+}
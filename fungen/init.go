@@ -0,0 +1,99 @@
+package fungen
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// directiveFile - the file of the package in which fungen init adds the //go:generate directive
+const directiveFile = "generate.go"
+
+// generateDirective - the //go:generate directive added by fungen init, which reads the default configuration file
+const generateDirective = "//go:generate fungen"
+
+// runInitCommand - propose a configuration file for the package in the current directory, with its named slice types,
+// and a //go:generate directive running fungen with it, and write them once confirmed
+func runInitCommand(args []string) {
+	flags := flag.NewFlagSet("init", flag.ExitOnError)
+	yes := flags.Bool("yes", false, "(Optional) Whether to write the proposed files without asking for confirmation.")
+	flags.Parse(args)
+
+	if _, err := os.Stat(defaultConfigFile); err == nil {
+		fatalf("the configuration file '%s' already exists", defaultConfigFile)
+	}
+	c := getInitConfig(".")
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		fatalf("encoding the configuration file as JSON: %s", err)
+	}
+	directiveSrc := getInitDirectiveSource(".", c.Package)
+
+	fmt.Printf("Proposed %s:\n\n%s\n\n", defaultConfigFile, content)
+	if directiveSrc != "" {
+		fmt.Printf("Proposed directive in %s:\n\n%s\n\n", directiveFile, generateDirective)
+	}
+	if len(c.Types) == 0 {
+		fmt.Printf("No slice types were found in the package, add the types to generate to the configuration file.\n\n")
+	}
+	if !*yes && !confirm("Write them?") {
+		return
+	}
+
+	if err := ioutil.WriteFile(defaultConfigFile, append(content, '\n'), 0644); err != nil {
+		fatalf("writing the configuration file '%s': %s", defaultConfigFile, err)
+	}
+	if directiveSrc != "" {
+		if err := ioutil.WriteFile(directiveFile, []byte(directiveSrc), 0644); err != nil {
+			fatalf("writing the directive to '%s': %s", directiveFile, err)
+		}
+	}
+	noticef("wrote %s, run go generate to generate the lists", defaultConfigFile)
+}
+
+// getInitConfig - get the proposed configuration of the package in dir: its package name, and its named slice types
+// along with the discovery of their lists, so that they are not declared again, if it has any
+func getInitConfig(dir string) config {
+	c := config{Package: "main", Output: "fungen_auto.go"}
+	if files := parsePackageFiles(token.NewFileSet(), dir, parser.PackageClauseOnly); len(files) > 0 {
+		c.Package = files[0].Name.Name
+	}
+	for _, list := range discoverSliceTypes(dir) {
+		c.Types = append(c.Types, configType{Type: list[0], List: list[1]})
+	}
+	if len(c.Types) > 0 {
+		c.Options = map[string]interface{}{"discover": true}
+	}
+	return c
+}
+
+// getInitDirectiveSource - get the source of the directive file of the package pkgName in dir, with the directive
+// added, or an empty string if a file of the package already has the directive
+func getInitDirectiveSource(dir, pkgName string) string {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, path := range paths {
+		if src, err := ioutil.ReadFile(path); err == nil && strings.Contains(string(src), generateDirective+"\n") {
+			return ""
+		}
+	}
+	if src, err := ioutil.ReadFile(filepath.Join(dir, directiveFile)); err == nil {
+		return strings.TrimRight(string(src), "\n") + "\n\n" + generateDirective + "\n"
+	}
+	return "package " + pkgName + "\n\n" + generateDirective + "\n"
+}
+
+// confirm - ask the question on the standard output and read the answer from the standard input, which is yes for
+// 'y' or 'yes'
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,73 @@
+package fungen
+
+import (
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	// keepBegin - the prefix of the line starting a region of a generated file which is written by hand and carried over
+	// when the file is generated again, optionally followed by a description of the region
+	keepBegin = "// fungen:keep-begin"
+	// keepEnd - the line ending a region written by hand
+	keepEnd = "// fungen:keep-end"
+)
+
+// getKeptRegions - get the regions written by hand at the top level of the source src of the file path, from their
+// begin line to their end line included. A region which is not ended is an error rather than being dropped, since it
+// would be lost
+func getKeptRegions(path, src string) []string {
+	regions := []string{}
+	region := ""
+	inRegion := false
+	for i, line := range strings.Split(src, "\n") {
+		if strings.HasPrefix(line, keepBegin) {
+			if inRegion {
+				fatalf("%s:%d: '%s' inside a region which is not ended", path, i+1, keepBegin)
+			}
+			region, inRegion = "", true
+		}
+		if inRegion {
+			region += line + "\n"
+		}
+		if line == keepEnd {
+			if !inRegion {
+				fatalf("%s:%d: '%s' without a '%s'", path, i+1, keepEnd, keepBegin)
+			}
+			regions = append(regions, region)
+			inRegion = false
+		}
+	}
+	if inRegion {
+		fatalf("%s: the region '%s' is not ended by '%s'", path, strings.TrimSpace(strings.SplitN(region, "\n", 2)[0]), keepEnd)
+	}
+	return regions
+}
+
+// getKeptSource - get the generated source src with the regions written by hand in the file path, if it was generated
+// by fungen, added at its end. The regions which are already in src, like the ones kept by -append or -merge along with
+// their section or declaration, are not added again. The imports of the file are kept for the code of the regions
+func getKeptSource(path, src string) string {
+	content, err := ioutil.ReadFile(path)
+	if err != nil || !isGeneratedSource(content) {
+		return src
+	}
+
+	kept := []string{}
+	for _, region := range getKeptRegions(path, string(content)) {
+		if !strings.Contains(src, strings.TrimSpace(region)) {
+			kept = append(kept, region)
+		}
+	}
+	if len(kept) == 0 {
+		return src
+	}
+	verbosef("kept %d regions written by hand in %s", len(kept), path)
+
+	_, file := parseSource(string(content))
+	knownImports := map[string]string{}
+	for _, spec := range file.Imports {
+		knownImports[getImportName(spec)] = getImportSpecSource(spec)
+	}
+	return getImportsSource(src+"\n"+strings.Join(kept, "\n"), knownImports)
+}
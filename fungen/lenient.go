@@ -0,0 +1,90 @@
+package fungen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// skippedProblems - the problems skipped with -lenient, which are summarized once the files are generated
+var skippedProblems = []string{}
+
+// skipOrFatalf - skip the problem with -lenient, adding it to the summary, or stop the generation with it otherwise
+func skipOrFatalf(format string, args ...interface{}) {
+	if !*lenient {
+		fatalf(format, args...)
+	}
+	skipf(format, args...)
+}
+
+// skipf - add the problem to the summary with -lenient, unless it is already there since the options are read
+// several times. The problems added without skipOrFatalf do not stop the generation without -lenient either
+func skipf(format string, args ...interface{}) {
+	if !*lenient {
+		return
+	}
+	problem := fmt.Sprintf(format, args...)
+	for _, skipped := range skippedProblems {
+		if skipped == problem {
+			return
+		}
+	}
+	skippedProblems = append(skippedProblems, problem)
+}
+
+// warnSkippedProblems - print the summary of the problems skipped with -lenient, if any
+func warnSkippedProblems() {
+	if len(skippedProblems) > 0 {
+		warnf("%d problems were skipped with -lenient:\n\t%s", len(skippedProblems), strings.Join(skippedProblems, "\n\t"))
+	}
+}
+
+// hasExplicitMethods - whether the methods of typeName are given, either in -methods or in -types, rather than all the
+// methods generated by default
+func hasExplicitMethods(typeName string) bool {
+	if methodsStr, ok := typeMethods[typeName]; ok {
+		return methodsStr != ""
+	}
+	return *methods != ""
+}
+
+// checkSupportedMethods - stop the generation, or skip the problem with -lenient, on a method given for a type in -types
+// which the type does not support, eg. Sum for a list of structs, and on a method of -methods which none of the types
+// it is given for supports. The methods of -methods which only some of the types support are generated for them
+func checkSupportedMethods(typeMap map[string]string, typeMethodsMaps map[string]map[string]bool, methodsMap map[string]bool) {
+	generators.Each(func(gen Generator) {
+		globalTypes := []string{}
+		supportedByGlobal := false
+		for _, k := range getSortedKeys(typeMap) {
+			if !typeMethodsMaps[k][gen.Name] {
+				continue
+			}
+			supported := isSupportedMethod(gen, k, typeMap)
+			if methodsStr, ok := typeMethods[k]; !ok {
+				globalTypes = append(globalTypes, k)
+				supportedByGlobal = supportedByGlobal || supported
+			} else if methodsStr != "" && !supported {
+				skipOrFatalf("%s.%s is not generated, it does not support the members of type %s", getListName(k, typeMap), gen.Name, k)
+			}
+		}
+		if *methods != "" && methodsMap[gen.Name] && len(globalTypes) > 0 && !supportedByGlobal {
+			skipOrFatalf("-methods: %s is not generated, it does not support the members of any of the types %s", gen.Name, strings.Join(globalTypes, ", "))
+		}
+	})
+}
+
+// isSupportedMethod - whether the method of the generator gen can be generated for typeName, with one of the types of
+// typeMap as target for the cross-type methods
+func isSupportedMethod(gen Generator, typeName string, typeMap map[string]string) bool {
+	if gen.Supports == nil {
+		return true
+	}
+	if !gen.NeedMapToMap {
+		return gen.Supports(typeName, "")
+	}
+	for k := range typeMap {
+		if gen.Supports(typeName, k) {
+			return true
+		}
+	}
+	return false
+}
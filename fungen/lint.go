@@ -0,0 +1,66 @@
+package fungen
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// linterNamePattern - the pattern of the names of the linters of -nolint, eg. 'gocyclo' or 'revive'
+var linterNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// getNolintDirective - get the //nolint directive for the comma-separated linters of -nolint, or for all the linters if
+// it is 'all'
+func getNolintDirective(linters string) string {
+	if linters == "all" {
+		return "//nolint"
+	}
+	names := strings.Split(linters, ",")
+	offsets := getPartOffsets(names)
+	for i, name := range names {
+		if !linterNamePattern.MatchString(name) {
+			fatalf("-nolint: '%s' is not a valid linter name%s", name, getTokenPointer(linters, offsets[i], offsets[i]+len(name)))
+		}
+	}
+	return "//nolint:" + linters
+}
+
+// validateLintFlags - check the values of -nolint and -coverage-ignore
+func validateLintFlags() {
+	if *nolint != "" {
+		getNolintDirective(*nolint)
+	}
+	if *coverIgnore != "" && (!strings.HasPrefix(*coverIgnore, "//") || strings.ContainsAny(*coverIgnore, "\r\n")) {
+		fatalf("-coverage-ignore '%s' must be a single line comment, eg. '//coverage:ignore'", *coverIgnore)
+	}
+}
+
+// getLintSource - annotate the generated source src of the file fileName for the linters and the coverage tools: each
+// top-level declaration, except the imports, gets the //nolint directive of -nolint after its doc comment, and the
+// files other than the test files get the comment of -coverage-ignore before their package clause. Both are directives
+// which are left out of the documentation
+func getLintSource(fileName, src string) string {
+	fset, file := parseSource(src)
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	edits := []edit{}
+	if *coverIgnore != "" && !strings.HasSuffix(fileName, "_test.go") {
+		edits = append(edits, edit{offset(file.Package), offset(file.Package), *coverIgnore + "\n"})
+	}
+	if *nolint != "" {
+		directive := getNolintDirective(*nolint)
+		for _, decl := range file.Decls {
+			if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+				continue
+			}
+			edits = append(edits, edit{offset(decl.Pos()), offset(decl.Pos()), directive + "\n"})
+		}
+	}
+	if len(edits) == 0 {
+		return src
+	}
+	return f(applyEdits(src, edits))
+}
@@ -0,0 +1,104 @@
+package fungen
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+// logLevel - the level of the messages printed by fungen
+type logLevel int
+
+const (
+	// quietLevel - only the errors are printed, with -q
+	quietLevel logLevel = iota
+	// normalLevel - the errors, the warnings and the notices are printed
+	normalLevel
+	// verboseLevel - the progress of the generation, by type and method, and its timings are also printed, with -v
+	verboseLevel
+)
+
+// logger - the logger of the messages of fungen, on the standard error
+var logger = log.New(os.Stderr, "fungen: ", 0)
+
+// getLogLevel - get the level of the messages to print from -v and -q
+func getLogLevel() logLevel {
+	if *verbose {
+		return verboseLevel
+	} else if *quiet {
+		return quietLevel
+	}
+	return normalLevel
+}
+
+// fatalError - the error raised by fatalf, which stops the generation
+type fatalError struct {
+	err error
+}
+
+// fatalf - stop the generation with the error, which is returned by the functions recovering it with recoverFatal,
+// so that the generation never exits the process of the programs importing fungen. Main prints it and exits with
+// status 1, whatever the level
+func fatalf(format string, args ...interface{}) {
+	panic(fatalError{fmt.Errorf(format, args...)})
+}
+
+// recoverFatal - recover the error raised by fatalf, if any, into err, which must be deferred by the functions
+// returning the errors of the generation. The other panics are not recovered
+func recoverFatal(err *error) {
+	if r := recover(); r != nil {
+		fatal, ok := r.(fatalError)
+		if !ok {
+			panic(r)
+		}
+		*err = fatal.err
+	}
+}
+
+// exitError - the error raised by exitWith, which stops the generation with the exit status code once its problem is
+// already reported, eg. by -check
+type exitError struct {
+	code int
+}
+
+// Error - the description of the exit error
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// exitWith - stop the generation with the exit status code, its problem being already reported. Unlike os.Exit, the
+// deferred functions run before Main exits, eg. to write the profiles of -cpuprofile
+func exitWith(code int) {
+	panic(fatalError{exitError{code}})
+}
+
+// exitOnError - print err and exit with status 1 if it is not nil, or exit with the status of an exit error
+func exitOnError(err error) {
+	if exitErr, ok := err.(exitError); ok {
+		os.Exit(exitErr.code)
+	}
+	if err != nil {
+		logger.Fatalf("Error: %s", err)
+	}
+}
+
+// warnf - print the warning unless -q is set
+func warnf(format string, args ...interface{}) {
+	if getLogLevel() >= normalLevel {
+		logger.Printf("Warning: "+format, args...)
+	}
+}
+
+// noticef - print the notice unless -q is set
+func noticef(format string, args ...interface{}) {
+	if getLogLevel() >= normalLevel {
+		logger.Printf(format, args...)
+	}
+}
+
+// verbosef - print the progress message if -v is set
+func verbosef(format string, args ...interface{}) {
+	if getLogLevel() >= verboseLevel {
+		logger.Printf(format, args...)
+	}
+}
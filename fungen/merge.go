@@ -0,0 +1,119 @@
+package fungen
+
+import (
+	"go/ast"
+	"go/token"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// getMergedSource - get the generated source src merged with the file path, if it was generated by fungen, keeping the
+// declarations of the file which belong to the types which are not generated in src, such as the list types along
+// with their methods and interfaces, and replacing the declarations of the types which are generated again
+func getMergedSource(path, src string) string {
+	content, err := ioutil.ReadFile(path)
+	if err != nil || !isGeneratedSource(content) {
+		return src
+	}
+
+	_, newFile := parseSource(src)
+	generated := map[string]bool{}
+	newTypes := getTypeDeclNames(newFile)
+	for _, decl := range newFile.Decls {
+		generated[getDeclOwner(decl, newTypes)] = true
+	}
+
+	oldSrc := string(content)
+	fset, oldFile := parseSource(oldSrc)
+	oldTypes := getTypeDeclNames(oldFile)
+	knownImports := map[string]string{}
+	for _, spec := range oldFile.Imports {
+		knownImports[getImportName(spec)] = getImportSpecSource(spec)
+	}
+
+	kept := []string{}
+	for _, decl := range oldFile.Decls {
+		if owner := getDeclOwner(decl, oldTypes); owner != "" && !generated[owner] {
+			start := decl.Pos()
+			if doc := getDeclDoc(decl); doc != nil {
+				start = doc.Pos()
+			}
+			kept = append(kept, oldSrc[fset.Position(start).Offset:fset.Position(decl.End()).Offset])
+		}
+	}
+	if len(kept) == 0 {
+		return src
+	}
+
+	return getImportsSource(src+"\n"+strings.Join(kept, "\n\n")+"\n", knownImports)
+}
+
+// getTypeDeclNames - get the names of the types declared at the top level of file, longest first
+func getTypeDeclNames(file *ast.File) []string {
+	names := []string{}
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
+			for _, spec := range genDecl.Specs {
+				names = append(names, spec.(*ast.TypeSpec).Name.Name)
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return len(names[i]) > len(names[j])
+	})
+	return names
+}
+
+// getDeclOwner - get the name of the generated type, among typeNames, to which the declaration decl belongs: the type
+// itself, the receiver type of a method, the list type of a function generated by -funcs, eg. 'intList' for
+// 'MapIntList', or the list type of an interface generated by -interfaces, eg. 'intList' for 'IntLister', along with
+// its assertion. The declarations which do not belong to a type, like the imports, have no owner
+func getDeclOwner(decl ast.Decl, typeNames []string) string {
+	name := ""
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if decl.Recv != nil {
+			return getReceiverListName(decl)
+		}
+		name = decl.Name.Name
+	case *ast.GenDecl:
+		if len(decl.Specs) == 0 {
+			return ""
+		}
+		switch spec := decl.Specs[0].(type) {
+		case *ast.TypeSpec:
+			name = spec.Name.Name
+		case *ast.ValueSpec:
+			if ident, ok := spec.Type.(*ast.Ident); ok {
+				name = ident.Name
+			}
+		}
+	}
+	if name == "" {
+		return ""
+	}
+
+	for _, typeName := range typeNames {
+		if name == typeName {
+			return typeName
+		}
+	}
+	for _, typeName := range typeNames {
+		if name == getExportedName(typeName)+"er" || strings.HasSuffix(name, getExportedName(typeName)) {
+			return typeName
+		}
+	}
+	return ""
+}
+
+// getDeclDoc - get the doc comment of the declaration decl, or nil if it has none
+func getDeclDoc(decl ast.Decl) *ast.CommentGroup {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		return decl.Doc
+	case *ast.GenDecl:
+		return decl.Doc
+	}
+	return nil
+}
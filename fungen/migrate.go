@@ -0,0 +1,665 @@
+package fungen
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// migrateHelper - a generic function of fungen migrate replacing a generated method, whose source declares the
+// function named name, with %[1]s for the prefix of the names, and uses the constraints and the packages it needs.
+// If convert is set, the function returns a plain slice, which the calls convert to the list type the method returns
+type migrateHelper struct {
+	name        string
+	src         string
+	constraints []string
+	imports     []string
+	convert     bool
+}
+
+// migrateConstraints - the sources of the type constraints used by the generic helpers, by name
+var migrateConstraints = map[string]string{
+	"fungenNumber": `
+// fungenNumber - the types whose values can be added and ordered
+type fungenNumber interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr | ~float32 | ~float64
+}
+`,
+	"fungenOrdered": `
+// fungenOrdered - the types whose values can be ordered
+type fungenOrdered interface {
+	fungenNumber | ~string
+}
+`,
+}
+
+// migrateHelpers - the generic functions replacing the generated methods, by generator name for the methods on the
+// list of the type itself and by generator name followed by 'To' for the cross-type methods, eg. 'MapTo' for
+// 'MapString'. The methods without a generic function are left as they are
+var migrateHelpers = map[string]migrateHelper{
+	"Map": {name: "Map", src: `
+// %[1]sMap - apply f to every member of l
+func %[1]sMap[L ~[]T, T any](l L, f func(T) T) L {
+	l2 := make(L, len(l))
+	for i, t := range l {
+		l2[i] = f(t)
+	}
+	return l2
+}
+`},
+	"MapTo": {name: "MapTo", convert: true, src: `
+// %[1]sMapTo - apply f to every member of l and return the list of the results
+func %[1]sMapTo[T, U any](l []T, f func(T) U) []U {
+	l2 := make([]U, len(l))
+	for i, t := range l {
+		l2[i] = f(t)
+	}
+	return l2
+}
+`},
+	"Filter": {name: "Filter", src: `
+// %[1]sFilter - get the members of l for which f returns true
+func %[1]sFilter[L ~[]T, T any](l L, f func(T) bool) L {
+	l2 := make(L, 0, len(l))
+	for _, t := range l {
+		if f(t) {
+			l2 = append(l2, t)
+		}
+	}
+	return l2
+}
+`},
+	"Reduce": {name: "Reduce", src: `
+// %[1]sReduce - apply f to t1 and all the members of l, starting from the first member
+func %[1]sReduce[T any](l []T, t1 T, f func(T, T) T) T {
+	for _, t := range l {
+		t1 = f(t1, t)
+	}
+	return t1
+}
+`},
+	"ReduceRight": {name: "ReduceRight", src: `
+// %[1]sReduceRight - apply f to all the members of l and t1, starting from the last member
+func %[1]sReduceRight[T any](l []T, t1 T, f func(T, T) T) T {
+	for i := len(l) - 1; i >= 0; i-- {
+		t1 = f(l[i], t1)
+	}
+	return t1
+}
+`},
+	"FoldTo": {name: "Fold", src: `
+// %[1]sFold - apply f to seed and all the members of l, starting from the first member
+func %[1]sFold[T, U any](l []T, seed U, f func(U, T) U) U {
+	for _, t := range l {
+		seed = f(seed, t)
+	}
+	return seed
+}
+`},
+	"Each": {name: "Each", src: `
+// %[1]sEach - apply f to each member of l and return l
+func %[1]sEach[L ~[]T, T any](l L, f func(T)) L {
+	for _, t := range l {
+		f(t)
+	}
+	return l
+}
+`},
+	"EachI": {name: "EachI", src: `
+// %[1]sEachI - apply f to the index and the value of each member of l and return l
+func %[1]sEachI[L ~[]T, T any](l L, f func(int, T)) L {
+	for i, t := range l {
+		f(i, t)
+	}
+	return l
+}
+`},
+	"All": {name: "All", src: `
+// %[1]sAll - whether f returns true for all the members of l, or l is empty
+func %[1]sAll[T any](l []T, f func(T) bool) bool {
+	for _, t := range l {
+		if !f(t) {
+			return false
+		}
+	}
+	return true
+}
+`},
+	"Any": {name: "Any", src: `
+// %[1]sAny - whether f returns true for at least one member of l
+func %[1]sAny[T any](l []T, f func(T) bool) bool {
+	for _, t := range l {
+		if f(t) {
+			return true
+		}
+	}
+	return false
+}
+`},
+	"Contains": {name: "Contains", src: `
+// %[1]sContains - whether l has a member equal to v
+func %[1]sContains[T comparable](l []T, v T) bool {
+	for _, t := range l {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+`},
+	"Take": {name: "Take", src: `
+// %[1]sTake - get the first n members of l, or l if it has fewer members
+func %[1]sTake[L ~[]T, T any](l L, n int) L {
+	if len(l) >= n {
+		return l[:n]
+	}
+	return l
+}
+`},
+	"Drop": {name: "Drop", src: `
+// %[1]sDrop - get the members of l after the first n, or an empty list if it has fewer members
+func %[1]sDrop[L ~[]T, T any](l L, n int) L {
+	if len(l) >= n {
+		return l[n:]
+	}
+	var l2 L
+	return l2
+}
+`},
+	"TakeWhile": {name: "TakeWhile", src: `
+// %[1]sTakeWhile - get the first members of l for which f returns true
+func %[1]sTakeWhile[L ~[]T, T any](l L, f func(T) bool) L {
+	for i, t := range l {
+		if !f(t) {
+			return l[:i]
+		}
+	}
+	return l
+}
+`},
+	"DropWhile": {name: "DropWhile", src: `
+// %[1]sDropWhile - get the members of l after the first ones for which f returns true
+func %[1]sDropWhile[L ~[]T, T any](l L, f func(T) bool) L {
+	for i, t := range l {
+		if !f(t) {
+			return l[i:]
+		}
+	}
+	var l2 L
+	return l2
+}
+`},
+	"Sum": {name: "Sum", constraints: []string{"fungenNumber"}, src: `
+// %[1]sSum - get the sum of the members of l
+func %[1]sSum[T fungenNumber](l []T) T {
+	var sum T
+	for _, t := range l {
+		sum += t
+	}
+	return sum
+}
+`},
+	"Min": {name: "Min", constraints: []string{"fungenNumber", "fungenOrdered"}, src: `
+// %[1]sMin - get the smallest member of l, or false if l is empty
+func %[1]sMin[T fungenOrdered](l []T) (T, bool) {
+	var result T
+	if len(l) == 0 {
+		return result, false
+	}
+	result = l[0]
+	for _, t := range l[1:] {
+		if t < result {
+			result = t
+		}
+	}
+	return result, true
+}
+`},
+	"Max": {name: "Max", constraints: []string{"fungenNumber", "fungenOrdered"}, src: `
+// %[1]sMax - get the largest member of l, or false if l is empty
+func %[1]sMax[T fungenOrdered](l []T) (T, bool) {
+	var result T
+	if len(l) == 0 {
+		return result, false
+	}
+	result = l[0]
+	for _, t := range l[1:] {
+		if t > result {
+			result = t
+		}
+	}
+	return result, true
+}
+`},
+	"Sort": {name: "Sort", constraints: []string{"fungenNumber", "fungenOrdered"}, imports: []string{"sort"}, src: `
+// %[1]sSort - sort the members of l in ascending order, in place, and return l
+func %[1]sSort[L ~[]T, T fungenOrdered](l L) L {
+	sort.Slice(l, func(i, j int) bool {
+		return l[i] < l[j]
+	})
+	return l
+}
+`},
+	"GroupBy": {name: "GroupBy", src: `
+// %[1]sGroupBy - get the lists of the members of l by the result of f for them, in their original order
+func %[1]sGroupBy[L ~[]T, T any, K comparable](l L, f func(T) K) map[K]L {
+	groups := map[K]L{}
+	for _, t := range l {
+		key := f(t)
+		groups[key] = append(groups[key], t)
+	}
+	return groups
+}
+`},
+	"CountBy": {name: "CountBy", src: `
+// %[1]sCountBy - get the numbers of the members of l by the result of f for them
+func %[1]sCountBy[T any, K comparable](l []T, f func(T) K) map[K]int {
+	counts := map[K]int{}
+	for _, t := range l {
+		counts[f(t)]++
+	}
+	return counts
+}
+`},
+	"FlatMap": {name: "FlatMap", src: `
+// %[1]sFlatMap - get the concatenation of the lists returned by f for every member of l
+func %[1]sFlatMap[T, U any, L ~[]U](l []T, f func(T) L) L {
+	l2 := L{}
+	for _, t := range l {
+		l2 = append(l2, f(t)...)
+	}
+	return l2
+}
+`},
+	"SumBy": {name: "SumBy", constraints: []string{"fungenNumber"}, src: `
+// %[1]sSumBy - get the sum of the results of f for all the members of l
+func %[1]sSumBy[T any, U fungenNumber](l []T, f func(T) U) U {
+	var sum U
+	for _, t := range l {
+		sum += f(t)
+	}
+	return sum
+}
+`},
+}
+
+func init() {
+	for _, name := range []string{"GroupBy", "CountBy", "FlatMap", "SumBy"} {
+		migrateHelpers[name+"To"] = migrateHelpers[name]
+	}
+}
+
+// getMigrateHelper - get the generic function replacing the generated method name, whose doc comment is doc, and
+// whether it has one. The generator of the method is the longest generator name, or name of the generator in one of
+// the styles of -style, the method name starts with, the rest being the name of the target type. The methods
+// returning copies, generated with -copy, have no generic function
+func getMigrateHelper(name, doc string) (migrateHelper, bool) {
+	genName, prefix := "", ""
+	generators.Each(func(gen Generator) {
+		for _, styled := range append([]string{gen.Name}, getStyledNames(gen.Name)...) {
+			if strings.HasPrefix(name, styled) && len(styled) > len(prefix) {
+				genName, prefix = gen.Name, styled
+			}
+		}
+	})
+	if genName == "" || strings.Contains(doc, "The result is a copy") {
+		return migrateHelper{}, false
+	}
+	if name != prefix {
+		genName += "To"
+	}
+	helper, ok := migrateHelpers[genName]
+	return helper, ok
+}
+
+// getStyledNames - get the names of the methods of the generator genName in the styles of -style which rename it
+func getStyledNames(genName string) []string {
+	names := []string{}
+	for _, styleNames := range methodStyles {
+		if name, ok := styleNames[genName]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// migration - the result of fungen migrate: the rewritten sources by path, the generic helpers they use and the
+// descriptions of the uses of the generated methods which are left as they are
+type migration struct {
+	pkgName   string
+	sources   map[string]string
+	helpers   map[string]migrateHelper
+	remaining []string
+	calls     int
+}
+
+// runMigrateCommand - rewrite the calls to the generated methods in the package of a directory into calls to generic
+// functions, written along with the package, and print the diff of the files or write them with -w
+func runMigrateCommand(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dir := flags.String("dir", ".", "(Optional) Directory of the package whose calls to the generated methods are rewritten.")
+	write := flags.Bool("w", false, "(Optional) Whether to write the rewritten files and the file of the generic functions instead of printing their diff.")
+	prefix := flags.String("prefix", "", "(Optional) Prefix of the names of the generic functions, eg. 'List' for 'ListMap', to avoid the names already declared in the package.")
+	helpersName := flags.String("filename", "fungen_generic.go", "(Optional) Name of the file of the generic functions, in the directory of the package.")
+	flags.Parse(args)
+
+	m := migratePackage(*dir, *prefix)
+	helpersPath := filepath.Join(*dir, *helpersName)
+	if len(m.helpers) > 0 {
+		m.sources[helpersPath] = getMigrateHelpersSource(helpersPath, m, *prefix)
+	}
+	if errs := checkMigratedPackage(*dir, m); len(errs) > 0 {
+		fatalf("the rewritten package does not type-check, nothing was written:\n%s", strings.Join(errs, "\n"))
+	}
+
+	for _, path := range getSortedKeys(m.sources) {
+		before, _ := ioutil.ReadFile(path)
+		if string(before) == m.sources[path] {
+			continue
+		}
+		if !*write {
+			fmt.Print(getUnifiedDiff(path, path, string(before), m.sources[path]))
+			continue
+		}
+		if err := writeFile(path, m.sources[path]); err != nil {
+			fatalf("writing '%s': %s", path, err)
+		}
+		verbosef("wrote %s", path)
+	}
+
+	noticef("%d calls to the generated methods are rewritten to generic functions", m.calls)
+	if len(m.remaining) > 0 {
+		warnf("the generated methods are still used, they cannot be removed yet:\n%s", strings.Join(m.remaining, "\n"))
+	} else if m.calls > 0 {
+		noticef("the generated methods are not used anymore, the generated files can be removed along with their directives")
+	}
+}
+
+// migratePackage - rewrite the calls to the generated methods on the list types in the files of the package in dir,
+// except the generated files and the external tests, into calls to the generic helpers named with prefix. The
+// package is type-checked to find the calls whatever their receiver expression
+func migratePackage(dir, prefix string) migration {
+	fset := token.NewFileSet()
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	files, generated := []*ast.File{}, map[*ast.File]bool{}
+	pkgName := ""
+	for _, path := range paths {
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			fatalf("reading '%s': %s", path, err)
+		}
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			fatalf("parsing '%s': %s", path, err)
+		}
+		if pkgName == "" && !strings.HasSuffix(path, "_test.go") {
+			pkgName = file.Name.Name
+		}
+		files = append(files, file)
+		generated[file] = isGeneratedSource(src)
+	}
+	if pkgName == "" {
+		fatalf("there is no package in '%s'", dir)
+	}
+
+	lists := map[string]map[string]string{}
+	pkgFiles := []*ast.File{}
+	for _, file := range files {
+		if file.Name.Name != pkgName {
+			continue
+		}
+		pkgFiles = append(pkgFiles, file)
+		if !generated[file] {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil {
+				if recvName := getReceiverListName(fn); recvName != "" {
+					if lists[recvName] == nil {
+						lists[recvName] = map[string]string{}
+					}
+					lists[recvName][fn.Name.Name] = fn.Doc.Text()
+				}
+			}
+		}
+	}
+
+	info := &gotypes.Info{Selections: map[*ast.SelectorExpr]*gotypes.Selection{}}
+	conf := gotypes.Config{Importer: typeImporter, Error: func(err error) {}}
+	pkg, _ := conf.Check(pkgName, fset, pkgFiles, info)
+
+	// getGeneratedMethod - get the list type and the name of the generated method selected by sel, if it is one
+	getGeneratedMethod := func(sel *ast.SelectorExpr) (listName string, ptr bool, ok bool) {
+		selection := info.Selections[sel]
+		if selection == nil || selection.Kind() != gotypes.MethodVal {
+			return "", false, false
+		}
+		recv := selection.Recv()
+		if p, isPtr := recv.(*gotypes.Pointer); isPtr {
+			recv, ptr = p.Elem(), true
+		}
+		named, isNamed := recv.(*gotypes.Named)
+		if !isNamed || named.Obj().Pkg() != pkg {
+			return "", false, false
+		}
+		_, ok = lists[named.Obj().Name()][sel.Sel.Name]
+		return named.Obj().Name(), ptr, ok
+	}
+
+	m := migration{pkgName: pkgName, sources: map[string]string{}, helpers: map[string]migrateHelper{}}
+	for _, file := range pkgFiles {
+		if generated[file] {
+			continue
+		}
+		calls := 0
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			listName, ptr, ok := getGeneratedMethod(sel)
+			if !ok {
+				return true
+			}
+			helper, ok := getMigrateHelper(sel.Sel.Name, lists[listName][sel.Sel.Name])
+			if !ok {
+				return true
+			}
+			recv := sel.X
+			if ptr {
+				recv = &ast.StarExpr{Star: sel.X.Pos(), X: sel.X}
+			}
+			fun := &ast.Ident{NamePos: sel.X.Pos(), Name: prefix + helper.name}
+			args := append([]ast.Expr{recv}, call.Args...)
+			if resultType := getMigrateResultType(info.Selections[sel], pkg, sel.X.Pos()); helper.convert && resultType != nil {
+				// the call is turned into the conversion of the call to the helper, eg. 'intList(MapTo(l, f))'
+				call.Fun, call.Args = resultType, []ast.Expr{&ast.CallExpr{Fun: fun, Lparen: call.Lparen, Args: args, Rparen: call.Rparen}}
+			} else {
+				call.Fun, call.Args = fun, args
+			}
+			m.helpers[helper.name] = helper
+			calls++
+			return true
+		})
+		ast.Inspect(file, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if listName, _, ok := getGeneratedMethod(sel); ok {
+					m.remaining = append(m.remaining, fmt.Sprintf("%s: %s.%s", fset.Position(sel.Sel.Pos()), listName, sel.Sel.Name))
+				}
+			}
+			return true
+		})
+		if calls == 0 {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, file); err != nil {
+			fatalf("printing '%s': %s", fset.Position(file.Pos()).Filename, err)
+		}
+		m.sources[fset.Position(file.Pos()).Filename] = buf.String()
+		m.calls += calls
+	}
+
+	sort.Strings(m.remaining)
+	if pkg != nil {
+		checkMigrateNames(pkg, fset, m, prefix)
+	}
+	return m
+}
+
+// getMigrateResultType - get the expression of the named type which the method of selection returns, at pos, or nil
+// if it does not return a single named type. The types of the package pkg are not qualified
+func getMigrateResultType(selection *gotypes.Selection, pkg *gotypes.Package, pos token.Pos) ast.Expr {
+	results := selection.Type().(*gotypes.Signature).Results()
+	if results.Len() != 1 {
+		return nil
+	}
+	named, ok := results.At(0).Type().(*gotypes.Named)
+	if !ok {
+		return nil
+	}
+	ident := &ast.Ident{NamePos: pos, Name: named.Obj().Name()}
+	if named.Obj().Pkg() == nil || named.Obj().Pkg() == pkg {
+		return ident
+	}
+	return &ast.SelectorExpr{X: &ast.Ident{NamePos: pos, Name: named.Obj().Pkg().Name()}, Sel: ident}
+}
+
+// checkMigratedPackage - type-check the package in dir, except its external tests, with the rewritten sources of m in
+// place of its files, and return the errors other than the imports which cannot be found, which are not caused by the
+// migration
+func checkMigratedPackage(dir string, m migration) []string {
+	fset := token.NewFileSet()
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for path := range m.sources {
+		if _, err := ioutil.ReadFile(path); err != nil {
+			paths = append(paths, path)
+		}
+	}
+	files := []*ast.File{}
+	for _, path := range paths {
+		src, ok := m.sources[path]
+		if !ok {
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				fatalf("reading '%s': %s", path, err)
+			}
+			src = string(content)
+		}
+		file, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil {
+			return []string{err.Error()}
+		}
+		if file.Name.Name == m.pkgName {
+			files = append(files, file)
+		}
+	}
+
+	errs := []string{}
+	conf := gotypes.Config{Importer: typeImporter, Error: func(err error) {
+		if typeErr, ok := err.(gotypes.Error); !ok || !strings.HasPrefix(typeErr.Msg, "could not import") {
+			errs = append(errs, err.Error())
+		}
+	}}
+	conf.Check(m.pkgName, fset, files, nil)
+	return errs
+}
+
+// checkMigrateNames - check that the names of the generic helpers of m and of their constraints, named with prefix,
+// are not declared in the package pkg, except in the file of the helpers written by a previous run
+func checkMigrateNames(pkg *gotypes.Package, fset *token.FileSet, m migration, prefix string) {
+	conflicts := []string{}
+	for _, name := range getMigrateNames(m, prefix) {
+		if obj := pkg.Scope().Lookup(name); obj != nil && !isMigrateHelpersFile(fset.Position(obj.Pos()).Filename) {
+			conflicts = append(conflicts, fmt.Sprintf("'%s' is already declared at %s", name, fset.Position(obj.Pos())))
+		}
+	}
+	if len(conflicts) > 0 {
+		fatalf("the generic functions conflict with the package, give them a prefix with -prefix:\n%s", strings.Join(conflicts, "\n"))
+	}
+}
+
+// migrateHelpersMarker - the comment identifying the files of the generic helpers written by fungen migrate
+const migrateHelpersMarker = "written by fungen migrate"
+
+// isMigrateHelpersFile - whether the file at path was written by fungen migrate
+func isMigrateHelpersFile(path string) bool {
+	src, err := ioutil.ReadFile(path)
+	return err == nil && bytes.Contains(src, []byte(migrateHelpersMarker))
+}
+
+// getMigrateNames - get the sorted names of the generic helpers of m and of their constraints, named with prefix
+func getMigrateNames(m migration, prefix string) []string {
+	names := map[string]string{}
+	for _, helper := range m.helpers {
+		names[prefix+helper.name] = ""
+		for _, constraint := range helper.constraints {
+			names[constraint] = ""
+		}
+	}
+	return getSortedKeys(names)
+}
+
+// getMigrateHelpersSource - get the source of the file at path with the generic helpers of m, named with prefix. The
+// declarations of the file written by a previous run are kept and only the missing helpers are added
+func getMigrateHelpersSource(path string, m migration, prefix string) string {
+	src := fmt.Sprintf("// Package %s - the generic functions replacing the methods generated by fungen, %s. Unlike the\n// generated files, this file can be edited.\npackage %s\n", m.pkgName, migrateHelpersMarker, m.pkgName)
+	declared := map[string]bool{}
+	if content, err := ioutil.ReadFile(path); err == nil && isMigrateHelpersFile(path) {
+		src = string(content)
+		_, file := parseSource(src)
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				declared[decl.Name.Name] = true
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok {
+						declared[spec.Name.Name] = true
+					}
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(src)
+	imports := map[string]string{}
+	helperNames := []string{}
+	for name := range m.helpers {
+		helperNames = append(helperNames, name)
+	}
+	sort.Strings(helperNames)
+	for _, name := range helperNames {
+		helper := m.helpers[name]
+		for _, constraint := range helper.constraints {
+			if !declared[constraint] {
+				b.WriteString(migrateConstraints[constraint])
+				declared[constraint] = true
+			}
+		}
+		if !declared[prefix+helper.name] {
+			fmt.Fprintf(&b, helper.src, prefix)
+			declared[prefix+helper.name] = true
+		}
+		for _, importPath := range helper.imports {
+			imports[importPath] = fmt.Sprintf("%q", importPath)
+		}
+	}
+	return getImportsSource(f(b.String()), imports)
+}
@@ -0,0 +1,52 @@
+package fungen
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// nameCasings - the casing strategies of -name-case, which derive the exported names of the types in the generated
+// names, eg. 'MapURL' or 'MapUrl' for the type 'url'
+var nameCasings = map[string]bool{
+	"initialisms": true,
+	"title":       true,
+}
+
+// commonInitialisms - the initialisms which are written in upper case in Go identifiers, as listed by golint
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "LHS": true, "QPS": true,
+	"RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true, "URI": true, "URL": true,
+	"UTF8": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// getExportedName - get the exported version of the identifier name, as it is written inside of the generated names,
+// eg. 'String' in 'MapString'. Its first rune is title cased, whatever its encoding, and with the initialisms casing
+// of -name-case a leading lowercase initialism is upper cased as a whole, eg. 'APIID' for 'apiID'
+func getExportedName(name string) string {
+	if name == "" {
+		return name
+	}
+	if *nameCase == "initialisms" {
+		end := strings.IndexFunc(name, func(r rune) bool {
+			return !unicode.IsLower(r) && !unicode.IsDigit(r)
+		})
+		if end < 0 {
+			end = len(name)
+		}
+		if word := strings.ToUpper(name[:end]); commonInitialisms[word] {
+			return word + name[end:]
+		}
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToTitle(r)) + name[size:]
+}
+
+// isExportedName - whether the generated names made of the identifier name are exported, which is the case unless it
+// starts with a lowercase letter
+func isExportedName(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return !unicode.IsLower(r)
+}
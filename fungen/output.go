@@ -0,0 +1,84 @@
+package fungen
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// outputBufferSize - the size of the buffer used to compare the generated files with the files on disk
+const outputBufferSize = 64 * 1024
+
+// isOverwritable - whether the file fileName can be written over: it does not exist, is empty or was generated by
+// fungen, so that a hand-written file named by a mistyped -filename is not destroyed
+func isOverwritable(fileName string) bool {
+	if fileName == "-" {
+		return true
+	}
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+	return len(bytes.TrimSpace(content)) == 0 || isGeneratedSource(content)
+}
+
+// isUpToDate - whether the file fileName has the content src, compared by chunks so that a large file is not read in
+// memory at once
+func isUpToDate(fileName, src string) bool {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	if info, err := file.Stat(); err != nil || info.Size() != int64(len(src)) {
+		return false
+	}
+
+	buf := make([]byte, outputBufferSize)
+	for offset := 0; ; {
+		n, err := file.Read(buf)
+		if n > len(src)-offset || string(buf[:n]) != src[offset:offset+n] {
+			return false
+		}
+		offset += n
+		if err == io.EOF {
+			return offset == len(src)
+		}
+		if err != nil {
+			return false
+		}
+	}
+}
+
+// writeFile - write src to the file fileName into a temporary file of its directory, which then replaces it, so that the
+// file is never left half written. The file keeps its permissions if it exists
+func writeFile(fileName, src string) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(fileName); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if target, err := filepath.EvalSymlinks(fileName); err == nil {
+		fileName = target
+	}
+
+	temp, err := ioutil.TempFile(filepath.Dir(fileName), "."+filepath.Base(fileName)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.WriteString(src); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Chmod(mode); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), fileName)
+}
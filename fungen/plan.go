@@ -0,0 +1,75 @@
+package fungen
+
+import (
+	"encoding/json"
+)
+
+// planPair - the types of the pair of a list in the plan
+type planPair struct {
+	First  string `json:"first"`
+	Second string `json:"second"`
+}
+
+// planList - a list type of the plan, with the type of its members, the types of its pair if it is the list of a pair,
+// and its methods as they are generated, including the cross-type methods, eg. 'MapString'
+type planList struct {
+	Name    string    `json:"name"`
+	Type    string    `json:"type"`
+	Pair    *planPair `json:"pair,omitempty"`
+	Methods []string  `json:"methods"`
+}
+
+// planFile - a file of the plan, with its list types and the test files generated along with it if they have
+// anything to test
+type planFile struct {
+	Name      string     `json:"name"`
+	Lists     []planList `json:"lists"`
+	TestFiles []string   `json:"testFiles,omitempty"`
+}
+
+// plan - the description of what fungen would generate, printed by -plan
+type plan struct {
+	Package string     `json:"package"`
+	Files   []planFile `json:"files"`
+}
+
+// getPlan - get the plan of the generation g, without generating any code
+func getPlan(g *generation) plan {
+	result := plan{Package: *packageName, Files: []planFile{}}
+	for _, contents := range getFileContents(g.typeMap) {
+		file := planFile{Name: getOutputPath(contents.fileName), Lists: []planList{}}
+		for _, k := range contents.typeKeys {
+			file.Lists = append(file.Lists, getPlanList(k, getListName(k, g.typeMap), g.typeMap, g.typeMethodsMaps[k]))
+		}
+		for _, pair := range contents.pairList {
+			pairName := getPairName(pair[0], pair[1], g.typeMap)
+			pairMap := getPairMap(pairName, g.typeMap)
+			list := getPlanList(pairName, getListName(pairName, pairMap), pairMap, getMethodsMap(*methods))
+			list.Pair = &planPair{pair[0], pair[1]}
+			file.Lists = append(file.Lists, list)
+		}
+		for _, kind := range getTestFileKinds() {
+			file.TestFiles = append(file.TestFiles, getOutputPath(getTestFileName(contents.fileName, kind.suffix)))
+		}
+		result.Files = append(result.Files, file)
+	}
+	return result
+}
+
+// getPlanList - get the plan of the list listName of typeName with the methods in methodsMap, as generated by generate
+func getPlanList(typeName, listName string, m map[string]string, methodsMap map[string]bool) planList {
+	list := planList{Name: listName, Type: typeName, Methods: []string{}}
+	eachMethod(typeName, m, methodsMap, func(gen Generator, targetType, targetTypeName string) {
+		list.Methods = append(list.Methods, getGeneratedMethodName(getMethodName(gen, targetTypeName)))
+	})
+	return list
+}
+
+// getPlanJSON - get the plan of the generation g as indented JSON
+func getPlanJSON(g *generation) string {
+	out, err := json.MarshalIndent(getPlan(g), "", "  ")
+	if err != nil {
+		fatalf("encoding the plan as JSON: %s", err)
+	}
+	return string(out)
+}
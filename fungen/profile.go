@@ -0,0 +1,49 @@
+package fungen
+
+import (
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// startProfiling - start the CPU profiling of fungen to the -cpuprofile file if it is set, and return the function
+// stopping it and writing the heap profile to the -memprofile file if it is set, which must be deferred. The profiles
+// are read with 'go tool pprof'
+func startProfiling() func() {
+	var cpuFile *os.File
+	if *cpuProfile != "" {
+		file, err := os.Create(*cpuProfile)
+		if err != nil {
+			fatalf("creating the CPU profile: %s", err)
+		}
+		if err := pprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			fatalf("starting the CPU profile: %s", err)
+		}
+		cpuFile = file
+	}
+	memProfilePath := *memProfile
+
+	return func() {
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			cpuFile.Close()
+			verbosef("wrote the CPU profile to %s", cpuFile.Name())
+		}
+		if memProfilePath == "" {
+			return
+		}
+		file, err := os.Create(memProfilePath)
+		if err != nil {
+			warnf("creating the memory profile: %s", err)
+			return
+		}
+		defer file.Close()
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(file); err != nil {
+			warnf("writing the memory profile: %s", err)
+			return
+		}
+		verbosef("wrote the memory profile to %s", memProfilePath)
+	}
+}
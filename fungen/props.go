@@ -0,0 +1,112 @@
+package fungen
+
+import (
+	"bytes"
+	"text/template"
+)
+
+// propsData - the data of the property test template: the list type, the type of its members, the name of the test,
+// the generated names of the methods of the list on its own type, by method name, and whether the members are numeric
+// or strings
+type propsData struct {
+	List    string
+	Type    string
+	Test    string
+	Methods map[string]string
+	Numeric bool
+}
+
+// propsTemplate - the template of the property test of a list type of ordered members, whose lists are generated by
+// testing/quick. The laws checked are that mapping with f then g is mapping with their composition, that filtering
+// with p then q is filtering with both, and that Reduce and ReduceRight agree for an associative function from its
+// identity: max from the zero value, which is also commutative, for the numeric types and concatenation from the
+// empty string for the strings
+var propsTemplate = template.Must(template.New("props").Option("missingkey=zero").Parse(`
+        func {{.Test}}(t *testing.T) {
+            {{- if or .Methods.Map .Methods.Filter}}
+            equal := func(a, b {{.List}}) bool {
+                if len(a) != len(b) {
+                    return false
+                }
+                for i := range a {
+                    if a[i] != b[i] {
+                        return false
+                    }
+                }
+                return true
+            }
+            {{- end}}
+            {{- with .Methods.Map}}
+            mapComposition := func(l {{$.List}}) bool {
+                f := func(v {{$.Type}}) {{$.Type}} { return {{if $.Numeric}}v + 1{{else}}v + "a"{{end}} }
+                g := func(v {{$.Type}}) {{$.Type}} { return {{if $.Numeric}}v * 2{{else}}"b" + v{{end}} }
+                mapped := l.{{.}}(f)
+                return equal(mapped.{{.}}(g), l.{{.}}(func(v {{$.Type}}) {{$.Type}} { return g(f(v)) }))
+            }
+            if err := quick.Check(mapComposition, nil); err != nil {
+                t.Errorf("{{.}}: mapping with f then g is not mapping with their composition: %s", err)
+            }
+            {{- end}}
+            {{- with .Methods.Filter}}
+            filterComposition := func(l {{$.List}}) bool {
+                p := func(v {{$.Type}}) bool { return {{if $.Numeric}}v > 10{{else}}len(v) > 1{{end}} }
+                q := func(v {{$.Type}}) bool { return {{if $.Numeric}}v < 100{{else}}v < "m"{{end}} }
+                filtered := l.{{.}}(p)
+                return equal(filtered.{{.}}(q), l.{{.}}(func(v {{$.Type}}) bool { return p(v) && q(v) }))
+            }
+            if err := quick.Check(filterComposition, nil); err != nil {
+                t.Errorf("{{.}}: filtering with p then q is not filtering with both: %s", err)
+            }
+            {{- end}}
+            {{- if and .Methods.Reduce .Methods.ReduceRight}}
+            reduceAgreement := func(l {{.List}}) bool {
+                {{- if .Numeric}}
+                f := func(a, b {{.Type}}) {{.Type}} {
+                    if a > b {
+                        return a
+                    }
+                    return b
+                }
+                {{- else}}
+                f := func(a, b {{.Type}}) {{.Type}} { return a + b }
+                {{- end}}
+                var identity {{.Type}}
+                return l.{{.Methods.Reduce}}(identity, f) == l.{{.Methods.ReduceRight}}(identity, f)
+            }
+            if err := quick.Check(reduceAgreement, nil); err != nil {
+                t.Errorf("{{.Methods.Reduce}} and {{.Methods.ReduceRight}} do not agree for an associative function: %s", err)
+            }
+            {{- end}}
+        }
+        `))
+
+// generatePropertyTests - generate the property test of the list listName of typeName, checking the laws of its
+// methods generated by generate on its own type, or an empty string if none of them is generated or if the members
+// are not ordered, as testing/quick only generates the lists of the basic types. The methods overridden by -templates
+// are not checked
+func generatePropertyTests(typeName, listName string, m map[string]string, methodsMap map[string]bool) string {
+	if !isOrderedType(typeName, "") {
+		return ""
+	}
+	data := propsData{
+		List:    listName,
+		Type:    typeName,
+		Test:    "Test" + getExportedName(listName) + "Properties",
+		Methods: map[string]string{},
+		Numeric: isNumericType(typeName, ""),
+	}
+	eachMethod(typeName, m, methodsMap, func(gen Generator, targetType, targetTypeName string) {
+		if _, overridden := methodTemplates[gen.Name]; targetTypeName == "" && !overridden {
+			data.Methods[gen.Name] = getGeneratedMethodName(getStyledName(gen.Name))
+		}
+	})
+	if data.Methods["Map"] == "" && data.Methods["Filter"] == "" && (data.Methods["Reduce"] == "" || data.Methods["ReduceRight"] == "") {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if err := propsTemplate.Execute(&buf, data); err != nil {
+		fatalf("property test template failed for type '%s': %s", typeName, err)
+	}
+	return "\n" + buf.String() + "\n"
+}
@@ -0,0 +1,140 @@
+package fungen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// referenceEntry - a declaration of the markdown reference of -docs: its name, its source without its body, and its doc
+// comment
+type referenceEntry struct {
+	name      string
+	signature string
+	doc       string
+}
+
+// referenceType - a type of the markdown reference of -docs, along with its methods in the order of their declaration
+type referenceType struct {
+	referenceEntry
+	methods []referenceEntry
+}
+
+// getDeclSource - get the source of the declaration decl, parsed in fset, as printed by gofmt
+func getDeclSource(fset *token.FileSet, decl ast.Decl) string {
+	var buf bytes.Buffer
+	config := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	config.Fprint(&buf, fset, decl)
+	return buf.String()
+}
+
+// getReferenceTypes - get the types declared in the generated files, except the test files, sorted by name, with their
+// methods, and the functions declared in them. Unlike the package comment of -gen-doc, the reference also has the
+// unexported types, since it is read outside of godoc
+func getReferenceTypes(files map[string]string) ([]referenceType, []referenceEntry) {
+	typesByName := map[string]*referenceType{}
+	funcs := []referenceEntry{}
+	methods := map[string][]referenceEntry{}
+	for _, fileName := range getSortedKeys(files) {
+		if strings.HasSuffix(fileName, "_test.go") {
+			continue
+		}
+		fset, file := parseSource(files[fileName])
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				signature := *decl
+				signature.Doc, signature.Body = nil, nil
+				entry := referenceEntry{decl.Name.Name, getDeclSource(fset, &signature), decl.Doc.Text()}
+				if decl.Recv == nil {
+					funcs = append(funcs, entry)
+				} else if recvName := getReceiverListName(decl); recvName != "" {
+					methods[recvName] = append(methods[recvName], entry)
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					spec := spec.(*ast.TypeSpec)
+					doc := spec.Doc
+					if doc == nil {
+						doc = decl.Doc
+					}
+					typeSpec := *spec
+					typeSpec.Doc, typeSpec.Comment = nil, nil
+					signature := getDeclSource(fset, &ast.GenDecl{Tok: token.TYPE, Specs: []ast.Spec{&typeSpec}})
+					typesByName[spec.Name.Name] = &referenceType{referenceEntry: referenceEntry{spec.Name.Name, signature, doc.Text()}}
+				}
+			}
+		}
+	}
+
+	types := []referenceType{}
+	for name, t := range typesByName {
+		t.methods = methods[name]
+		types = append(types, *t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].name < types[j].name })
+	return types, funcs
+}
+
+// generateMarkdownReference - generate the markdown reference of -docs of the types, methods and functions declared in
+// the generated files, with their signatures and doc comments, so that the generated API can be read without the
+// sources, eg. by people who do not read Go
+func generateMarkdownReference(files map[string]string) string {
+	types, funcs := getReferenceTypes(files)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated API of package %s\n\n", *packageName)
+	fmt.Fprintf(&b, "<!-- %s -->\n\n", generatedMarker)
+	fmt.Fprintf(&b, "The types and functions generated by fungen with `%s`.\n", commandLine)
+	addEntry := func(level, title string, entry referenceEntry) {
+		fmt.Fprintf(&b, "\n%s %s\n\n```go\n%s\n```\n", level, title, entry.signature)
+		if doc := strings.TrimSpace(entry.doc); doc != "" {
+			fmt.Fprintf(&b, "\n%s\n", doc)
+		}
+	}
+
+	if len(types) > 0 {
+		b.WriteString("\n## Types\n\n")
+		for _, t := range types {
+			fmt.Fprintf(&b, "- [%[1]s](#%[2]s)\n", t.name, strings.ToLower(t.name))
+		}
+	}
+	for _, t := range types {
+		addEntry("##", t.name, t.referenceEntry)
+		for _, method := range t.methods {
+			addEntry("###", t.name+"."+method.name, method)
+		}
+	}
+	if len(funcs) > 0 {
+		b.WriteString("\n## Functions\n")
+		for _, fn := range funcs {
+			addEntry("###", fn.name, fn)
+		}
+	}
+	return b.String()
+}
+
+// writeReference - write the markdown reference src to the file path, unless it is up to date, as writeOutput does
+func writeReference(path, src string) error {
+	if *testrun {
+		fmt.Println(path)
+		fmt.Println(src)
+		return nil
+	}
+	if !*forceWrite && isUpToDate(path, src) {
+		verbosef("%s is up to date", path)
+		return nil
+	}
+	if err := writeFile(path, src); err != nil {
+		return fmt.Errorf("writing the reference '%s': %s", path, err)
+	}
+	verbosef("wrote %s", path)
+	return nil
+}
@@ -0,0 +1,394 @@
+package fungen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// edit - a replacement of the source between two byte offsets
+type edit struct {
+	start int
+	end   int
+	text  string
+}
+
+// applyEdits - apply the non-overlapping edits to src
+func applyEdits(src string, edits []edit) string {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+	var b strings.Builder
+	last := 0
+	for _, e := range edits {
+		b.WriteString(src[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.WriteString(src[last:])
+	return b.String()
+}
+
+// parseSource - parse the generated source along with its comments
+func parseSource(src string) (*token.FileSet, *ast.File) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		fatalf("the generated code cannot be parsed, which may come from a -templates file or a type: %s", err)
+	}
+	return fset, file
+}
+
+// getListMethods - get the methods declared in file on the list types in lists, including the methods with pointer
+// receivers if withPointers is true
+func getListMethods(file *ast.File, lists map[string]string, withPointers bool) []*ast.FuncDecl {
+	result := []*ast.FuncDecl{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil {
+			continue
+		}
+		if _, ok := fn.Recv.List[0].Type.(*ast.StarExpr); ok && !withPointers {
+			continue
+		}
+		if _, ok := lists[getReceiverListName(fn)]; ok {
+			result = append(result, fn)
+		}
+	}
+	return result
+}
+
+// getReceiverListName - get the name of the type of the receiver of the method fn, without the pointer
+func getReceiverListName(fn *ast.FuncDecl) string {
+	recvType := fn.Recv.List[0].Type
+	if star, ok := recvType.(*ast.StarExpr); ok {
+		recvType = star.X
+	}
+	if ident, ok := recvType.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// inspectWithParent - traverse node like ast.Inspect, calling f with each node and its parent
+func inspectWithParent(node ast.Node, f func(n, parent ast.Node)) {
+	stack := []ast.Node{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		if len(stack) > 0 {
+			f(n, stack[len(stack)-1])
+		}
+		stack = append(stack, n)
+		return true
+	})
+}
+
+// getFuncsSource - rewrite the methods on the list types in src into package-level functions which take the list as
+// a plain slice for their first parameter, eg. 'func (l intList) Map(...)' becomes 'func MapIntList(l []int, ...)'.
+// lists maps the names of the list types to the types of their members
+func getFuncsSource(src string, lists map[string]string) string {
+	fset, file := parseSource(src)
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	names := []string{}
+	for listName := range lists {
+		names = append(names, regexp.QuoteMeta(listName))
+	}
+	listNames := regexp.MustCompile(`\b(` + strings.Join(names, "|") + `)\b`)
+
+	edits := []edit{}
+	for _, fn := range getListMethods(file, lists, false) {
+		recv := fn.Recv.List[0]
+		listName := recv.Type.(*ast.Ident).Name
+		funcName := fn.Name.Name + getExportedName(listName)
+		if *unexported {
+			funcName = getUnexportedName(funcName)
+		}
+
+		params := "func " + funcName + "(" + recv.Names[0].Name + " []" + lists[listName]
+		if len(fn.Type.Params.List) > 0 {
+			params += ", "
+		}
+		edits = append(edits, edit{offset(fn.Type.Func), offset(fn.Type.Params.Opening) + 1, params})
+
+		if fn.Doc != nil {
+			for i, c := range fn.Doc.List {
+				text := c.Text
+				if i == 0 && strings.HasPrefix(text, "// "+fn.Name.Name+" ") {
+					text = "// " + funcName + text[len("// "+fn.Name.Name):]
+				}
+				text = strings.Replace(text, " a method on ", " a function on ", -1)
+				text = listNames.ReplaceAllStringFunc(text, func(name string) string {
+					return "[]" + lists[name]
+				})
+				edits = append(edits, edit{offset(c.Pos()), offset(c.End()), text})
+			}
+		}
+
+		ast.Inspect(fn, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok || ident.Pos() < fn.Type.Params.Opening {
+				return true
+			}
+			if typeName, ok := lists[ident.Name]; ok {
+				edits = append(edits, edit{offset(ident.Pos()), offset(ident.End()), "[]" + typeName})
+			}
+			return true
+		})
+	}
+
+	return f(applyEdits(src, edits))
+}
+
+// getInterfacesSource - add an interface describing the methods of each of the list types in src, along with an
+// assertion that the list type satisfies it, eg. 'IntLister' for 'intList'. lists maps the names of the list types to
+// the types of their members
+func getInterfacesSource(src string, lists map[string]string) string {
+	fset, file := parseSource(src)
+
+	methods := map[string][]string{}
+	for _, fn := range getListMethods(file, lists, *ptrReceiver) {
+		var sig bytes.Buffer
+		if err := printer.Fprint(&sig, fset, fn.Type); err != nil {
+			fatalf("printing the signature of the %s method for -interfaces: %s", fn.Name.Name, err)
+		}
+		listName := getReceiverListName(fn)
+		methods[listName] = append(methods[listName], fn.Name.Name+strings.TrimPrefix(sig.String(), "func"))
+	}
+
+	listNames := []string{}
+	for listName := range methods {
+		listNames = append(listNames, listName)
+	}
+	sort.Strings(listNames)
+
+	var b strings.Builder
+	b.WriteString(src)
+	for _, listName := range listNames {
+		interfaceName := getExportedName(listName) + "er"
+		implementation := listName
+		if *ptrReceiver {
+			implementation = "(*" + listName + ")"
+		}
+		fmt.Fprintf(&b, `
+            // %[1]s is the interface describing the methods generated for %[2]s
+            type %[1]s interface {
+                %[3]s
+            }
+
+            // %[2]s must satisfy %[1]s
+            var _ %[1]s = %[2]s(nil)
+            `, interfaceName, implementation, strings.Join(methods[listName], "\n"))
+	}
+
+	return f(b.String())
+}
+
+// getPointerReceiversSource - rewrite the methods on the list types in src to have pointer receivers, eg.
+// 'func (l intList) Take(n int) intList' becomes 'func (l *intList) Take(n int) intList', dereferencing the receiver
+// wherever it is used. lists maps the names of the list types to the types of their members
+func getPointerReceiversSource(src string, lists map[string]string) string {
+	fset, file := parseSource(src)
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	edits := []edit{}
+	for _, fn := range getListMethods(file, lists, false) {
+		recv := fn.Recv.List[0]
+		edits = append(edits, edit{offset(recv.Type.Pos()), offset(recv.Type.Pos()), "*"})
+		if len(recv.Names) == 0 || recv.Names[0].Obj == nil {
+			continue
+		}
+
+		recvObj := recv.Names[0].Obj
+		inspectWithParent(fn.Body, func(n, parent ast.Node) {
+			ident, ok := n.(*ast.Ident)
+			if !ok || ident.Obj != recvObj {
+				return
+			}
+			text := "*" + ident.Name
+			switch parent := parent.(type) {
+			case *ast.SelectorExpr:
+				return
+			case *ast.IndexExpr:
+				if parent.X == ident {
+					text = "(" + text + ")"
+				}
+			case *ast.SliceExpr:
+				if parent.X == ident {
+					text = "(" + text + ")"
+				}
+			}
+			edits = append(edits, edit{offset(ident.Pos()), offset(ident.End()), text})
+		})
+	}
+
+	return f(applyEdits(src, edits))
+}
+
+// interfaceMethods - the names of the generated methods which implement standard interfaces and must stay exported
+var interfaceMethods = map[string]bool{
+	"String":      true,
+	"Value":       true,
+	"Scan":        true,
+	"MarshalJSON": true,
+}
+
+// getUnexportedName - get the unexported version of the method name, eg. 'filter' for 'Filter'. Names which would be
+// keywords get the 'List' suffix, eg. 'mapList' for 'Map'
+func getUnexportedName(name string) string {
+	name = strings.ToLower(name[:1]) + name[1:]
+	if token.Lookup(name).IsKeyword() {
+		name += "List"
+	}
+	return name
+}
+
+// getUnexportedSource - rename the methods on the list types in src to unexported names, except for the methods
+// implementing standard interfaces. lists maps the names of the list types to the types of their members
+func getUnexportedSource(src string, lists map[string]string) string {
+	fset, file := parseSource(src)
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	edits := []edit{}
+	for _, fn := range getListMethods(file, lists, true) {
+		name := fn.Name.Name
+		if interfaceMethods[name] || !ast.IsExported(name) {
+			continue
+		}
+		edits = append(edits, edit{offset(fn.Name.Pos()), offset(fn.Name.End()), getUnexportedName(name)})
+		if fn.Doc != nil && strings.HasPrefix(fn.Doc.List[0].Text, "// "+name+" ") {
+			start := offset(fn.Doc.List[0].Slash) + len("// ")
+			edits = append(edits, edit{start, start + len(name), getUnexportedName(name)})
+		}
+	}
+
+	return f(applyEdits(src, edits))
+}
+
+// getReceiverName - get the receiver name for the list type listName from the -receiver option, which is either an
+// identifier or 'auto' for the lowercased initials of the words of the list type name, eg. 'il' for 'intList'
+func getReceiverName(listName string) string {
+	if *receiver != "auto" {
+		return *receiver
+	}
+
+	r, size := utf8.DecodeRuneInString(listName)
+	name := string(unicode.ToLower(r))
+	for _, r := range listName[size:] {
+		if unicode.IsUpper(r) {
+			name += string(unicode.ToLower(r))
+		}
+	}
+	return name
+}
+
+// getReceiversSource - rename the receivers of the methods on the list types in src according to the -receiver
+// option. lists maps the names of the list types to the types of their members
+func getReceiversSource(src string, lists map[string]string) string {
+	fset, file := parseSource(src)
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	edits := []edit{}
+	for _, fn := range getListMethods(file, lists, true) {
+		recv := fn.Recv.List[0]
+		if len(recv.Names) == 0 || recv.Names[0].Obj == nil {
+			continue
+		}
+
+		name := getReceiverName(getReceiverListName(fn))
+		recvObj := recv.Names[0].Obj
+		ast.Inspect(fn, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			if ident.Obj == recvObj || ident == recv.Names[0] {
+				edits = append(edits, edit{offset(ident.Pos()), offset(ident.End()), name})
+			} else if ident.Name == name {
+				fatalf("-receiver name '%s' conflicts with an identifier used in the %s method", name, fn.Name.Name)
+			}
+			return true
+		})
+	}
+
+	return f(applyEdits(src, edits))
+}
+
+// getImportsSource - manage the imports of src: remove the imports which are not used, which happens when the
+// generators or types which need them are generated in other files, and add the imports which are used but missing
+// from knownImports, which maps package names to their import specs. The imports are merged into one sorted declaration
+func getImportsSource(src string, knownImports map[string]string) string {
+	fset, file := parseSource(src)
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	used := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil {
+				used[x.Name] = true
+			}
+		}
+		return true
+	})
+
+	specs := map[string]bool{}
+	imported := map[string]bool{}
+	edits := []edit{}
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			spec := spec.(*ast.ImportSpec)
+			name := getImportName(spec)
+			imported[name] = true
+			if used[name] {
+				specs[src[offset(spec.Pos()):offset(spec.End())]] = true
+			}
+		}
+		end := offset(genDecl.End())
+		if end < len(src) && src[end] == '\n' {
+			end++
+		}
+		edits = append(edits, edit{offset(genDecl.Pos()), end, ""})
+	}
+	for name := range used {
+		if spec, ok := knownImports[name]; ok && !imported[name] && file.Scope.Lookup(name) == nil {
+			specs[spec] = true
+		}
+	}
+
+	sorted := []string{}
+	for spec := range specs {
+		sorted = append(sorted, spec)
+	}
+	sort.Strings(sorted)
+	if len(sorted) > 0 {
+		imports := "\n\nimport (\n" + strings.Join(sorted, "\n") + "\n)\n"
+		edits = append(edits, edit{offset(file.Name.End()), offset(file.Name.End()), imports})
+	}
+
+	return f(applyEdits(src, edits))
+}
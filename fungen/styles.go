@@ -0,0 +1,73 @@
+package fungen
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// methodStyles - the naming styles of -style, which map the names of the generators to the names of the generated
+// methods when they differ: the fungen names by default, or the names of the equivalent functions of samber/lo for
+// 'lo' or of the standard slices package for 'slices'. The methods without an equivalent keep their fungen names
+var methodStyles = map[string]map[string]string{
+	"fungen": {},
+	"lo": {
+		"Each":       "ForEach",
+		"All":        "EveryBy",
+		"Any":        "SomeBy",
+		"CountBy":    "CountValuesBy",
+		"CompactNil": "Compact",
+		"Zip":        "ZipBy",
+	},
+	"slices": {
+		"Any": "ContainsFunc",
+	},
+}
+
+// getStyledName - get the name of the method generated by the generator genName in the naming style of -style, eg.
+// 'ForEach' for 'Each' with -style lo
+func getStyledName(genName string) string {
+	if name, ok := methodStyles[*style][genName]; ok {
+		return name
+	}
+	return genName
+}
+
+// getStyleGenerator - get the name of the generator of the generated method name, which is the longest generator name
+// it starts with since the cross-type methods are suffixed with the name of their target type, eg. 'CountBy' for
+// 'CountByInt', or an empty string if it has none
+func getStyleGenerator(name string) string {
+	genName := ""
+	generators.Each(func(gen Generator) {
+		if strings.HasPrefix(name, gen.Name) && len(gen.Name) > len(genName) {
+			genName = gen.Name
+		}
+	})
+	return genName
+}
+
+// getStyledSource - rename the methods on the list types in src, along with the first word of their doc comments, to
+// their names in the naming style of -style. lists maps the names of the list types to the types of their members
+func getStyledSource(src string, lists map[string]string) string {
+	fset, file := parseSource(src)
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	edits := []edit{}
+	for _, fn := range getListMethods(file, lists, true) {
+		name := fn.Name.Name
+		genName := getStyleGenerator(name)
+		if genName == "" || getStyledName(genName) == genName || !ast.IsExported(name) {
+			continue
+		}
+		styled := getStyledName(genName) + strings.TrimPrefix(name, genName)
+		edits = append(edits, edit{offset(fn.Name.Pos()), offset(fn.Name.End()), styled})
+		if fn.Doc != nil && strings.HasPrefix(fn.Doc.List[0].Text, "// "+name+" ") {
+			start := offset(fn.Doc.List[0].Slash) + len("// ")
+			edits = append(edits, edit{start, start + len(name), styled})
+		}
+	}
+
+	return f(applyEdits(src, edits))
+}
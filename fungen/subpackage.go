@@ -0,0 +1,160 @@
+package fungen
+
+import (
+	"go/ast"
+	"go/build"
+	"go/token"
+	gotypes "go/types"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// parentPackage - the package of the member types with -subpackage, which the generated subpackage imports: its name
+// and import path
+var parentPackage struct {
+	name, path string
+}
+
+// prepareSubpackage - validate -subpackage and find the name and the import path of the package of the member types,
+// in the output directory, which is the parent of the subpackage. The subpackage is named after its directory
+// unless -package is set
+func prepareSubpackage() {
+	if !token.IsIdentifier(*subpackage) {
+		fatalf("-subpackage '%s' is not a valid package name", *subpackage)
+	}
+	if *outputName == "-" {
+		fatalf("-subpackage cannot be used with the standard output")
+	}
+	if *discover {
+		fatalf("-subpackage and -discover cannot be used together, since the methods of the discovered lists must be declared in their package")
+	}
+
+	name := getDefaultPackageName()
+	if name == "main" {
+		fatalf("-subpackage cannot be used from package main, which cannot be imported")
+	}
+	dir, err := filepath.Abs(getOutputDir())
+	if err != nil {
+		fatalf("-subpackage: %s", err)
+	}
+	importPath := getImportPath(dir)
+	if importPath == "" {
+		fatalf("-subpackage: cannot find the import path of the package in '%s', which is neither in a module nor in GOPATH", dir)
+	}
+	parentPackage.name, parentPackage.path = name, importPath
+	if !isFlagSet("package") {
+		*packageName = *subpackage
+	}
+}
+
+// getImportPath - get the import path of the package in the absolute directory dir, from the go.mod file of its module
+// or from GOPATH, or an empty string if it has none
+func getImportPath(dir string) string {
+	for modDir := dir; ; modDir = filepath.Dir(modDir) {
+		if content, err := ioutil.ReadFile(filepath.Join(modDir, "go.mod")); err == nil {
+			modulePath := getModulePath(content)
+			if modulePath == "" {
+				return ""
+			}
+			rel, _ := filepath.Rel(modDir, dir)
+			return path.Join(modulePath, filepath.ToSlash(rel))
+		}
+		if filepath.Dir(modDir) == modDir {
+			break
+		}
+	}
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		rel, err := filepath.Rel(filepath.Join(gopath, "src"), dir)
+		if err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return ""
+}
+
+// getModulePath - get the module path declared in the content of a go.mod file, or an empty string if it has none
+func getModulePath(content []byte) string {
+	for _, line := range strings.Split(string(content), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+// qualifyParentTypes - qualify the identifiers of the generated files, by file name, which refer to the declarations of
+// the parent package with its name, eg. 'models.User' for 'User', and import it. The identifiers declared in the
+// generated files are left alone, and the unexported declarations of the parent package cannot be used
+func qualifyParentTypes(files map[string]string) {
+	parentDecls := getPackageDecls()
+	generated := fileDecls{map[string]token.Position{}, map[string]map[string]token.Position{}}
+	for _, src := range files {
+		fset, file := parseSource(src)
+		addFileDecls(generated, fset, file)
+	}
+	knownImports := map[string]string{parentPackage.name: getImportSpec(parentPackage.name, map[string]string{parentPackage.name: parentPackage.path})}
+
+	for fileName, src := range files {
+		fset, file := parseSource(src)
+		skipped := map[*ast.Ident]bool{file.Name: true}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				skipped[n.Sel] = true
+			case *ast.KeyValueExpr:
+				if key, ok := n.Key.(*ast.Ident); ok {
+					skipped[key] = true
+				}
+			case *ast.FuncDecl:
+				skipped[n.Name] = true
+			}
+			return true
+		})
+
+		edits := []edit{}
+		ast.Inspect(file, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok || ident.Obj != nil || skipped[ident] {
+				return true
+			}
+			if _, ok := parentDecls.names[ident.Name]; !ok {
+				return true
+			}
+			if _, ok := generated.names[ident.Name]; ok {
+				return true
+			}
+			if !ident.IsExported() {
+				fatalf("-subpackage: '%s' is not exported by package %s, so the subpackage cannot use it", ident.Name, parentPackage.name)
+			}
+			offset := fset.Position(ident.Pos()).Offset
+			edits = append(edits, edit{offset, offset, parentPackage.name + "."})
+			return true
+		})
+		if len(edits) > 0 {
+			files[fileName] = getImportsSource(applyEdits(src, edits), knownImports)
+		}
+	}
+}
+
+// subpackageImporter - the importer of the type checks of the generated subpackage, which gives the type-checked parent
+// package for its import path and imports the other packages as usual
+type subpackageImporter struct {
+	parent *gotypes.Package
+}
+
+// Import - import the package of the import path importPath
+func (i subpackageImporter) Import(importPath string) (*gotypes.Package, error) {
+	if importPath == parentPackage.path {
+		return i.parent, nil
+	}
+	return typeImporter.Import(importPath)
+}
+
+// getSubpackageImporter - type-check the parent package and get the importer of the generated subpackage
+func getSubpackageImporter() gotypes.Importer {
+	conf := gotypes.Config{Importer: typeImporter, Error: func(err error) {}}
+	parent, _ := conf.Check(parentPackage.path, packageFset, packageFiles, nil)
+	return subpackageImporter{parent}
+}
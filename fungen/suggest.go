@@ -0,0 +1,148 @@
+package fungen
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// getEditDistance - get the optimal string alignment distance between a and b, ignoring the case, in runes: the
+// Levenshtein distance where swapping two adjacent runes, a common typo as in 'Mpa' for 'Map', counts as one edit
+func getEditDistance(a, b string) int {
+	ra, rb := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	beforePrevious, previous := []int(nil), make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current := make([]int, len(rb)+1)
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && beforePrevious[j-2]+1 < current[j] {
+				current[j] = beforePrevious[j-2] + 1
+			}
+		}
+		beforePrevious, previous = previous, current
+	}
+	return previous[len(rb)]
+}
+
+// min3 - get the smallest of a, b and c
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// getSuggestion - get the candidate closest to name, which can differ from it by a third of its length at most, or an
+// empty string if none is close enough
+func getSuggestion(name string, candidates []string) string {
+	best, bestDistance := "", utf8.RuneCountInString(name)/3+1
+	for _, candidate := range candidates {
+		if distance := getEditDistance(name, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// didYouMean - get the suggestion of the candidate closest to name appended to the errors, eg. ", did you mean
+// 'Map'?", or an empty string if none is close enough
+func didYouMean(name string, candidates []string) string {
+	if suggestion := getSuggestion(name, candidates); suggestion != "" {
+		return fmt.Sprintf(", did you mean '%s'?", suggestion)
+	}
+	return ""
+}
+
+// getTokenPointer - get the lines appended to the errors to show the offending token of value, from the byte offsets
+// start to end, the value on the first line and carets under the token on the second
+func getTokenPointer(value string, start, end int) string {
+	if end > len(value) {
+		end = len(value)
+	}
+	width := utf8.RuneCountInString(value[start:end])
+	if width == 0 {
+		width = 1
+	}
+	return "\n\t" + value + "\n\t" + strings.Repeat(" ", utf8.RuneCountInString(value[:start])) + strings.Repeat("^", width)
+}
+
+// getPartOffsets - get the byte offsets of the parts of a string split by a separator of one byte
+func getPartOffsets(parts []string) []int {
+	offsets := make([]int, len(parts))
+	for i := 1; i < len(parts); i++ {
+		offsets[i] = offsets[i-1] + len(parts[i-1]) + 1
+	}
+	return offsets
+}
+
+// getMethodNames - get the names of the methods which can be generated, which are the suggestions for the unknown
+// methods
+func getMethodNames() []string {
+	names := []string{}
+	generators.Each(func(gen Generator) {
+		names = append(names, gen.Name)
+	})
+	return names
+}
+
+// getFlagNames - get the names of the flags of fungen, which are the suggestions for the undefined flags
+func getFlagNames() []string {
+	names := []string{}
+	flagSet.VisitAll(func(f *flag.Flag) {
+		names = append(names, f.Name)
+	})
+	return names
+}
+
+// parseFlags - parse the flags of fungen in args, exiting with the usage as the flag package does when a flag is not
+// defined, along with the suggestion of the closest flag
+func parseFlags(args []string) {
+	if name := getUndefinedFlag(flagSet, args); name != "" {
+		message := "flag provided but not defined: -" + name
+		if suggestion := getSuggestion(name, getFlagNames()); suggestion != "" {
+			message += ", did you mean -" + suggestion + "?"
+		}
+		fmt.Fprintln(flagSet.Output(), message)
+		flagSet.Usage()
+		os.Exit(2)
+	}
+	flagSet.Parse(args)
+}
+
+// getUndefinedFlag - get the name of the first flag in args which is not defined in flags, parsing them as the flag
+// package does, or an empty string if they are all defined
+func getUndefinedFlag(flags *flag.FlagSet, args []string) string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' || arg == "--" {
+			return ""
+		}
+		name := strings.TrimPrefix(arg[1:], "-")
+		hasValue := strings.Contains(name, "=")
+		name = strings.SplitN(name, "=", 2)[0]
+		if name == "h" || name == "help" {
+			continue
+		}
+		f := flags.Lookup(name)
+		if f == nil {
+			return name
+		}
+		if !hasValue && !isBoolFlag(f) {
+			i++
+		}
+	}
+	return ""
+}
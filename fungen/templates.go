@@ -0,0 +1,186 @@
+package fungen
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// templateExt - the extension of the files of the -templates directory, which are named after the method they
+// override, eg. 'Map.tmpl'
+const templateExt = ".tmpl"
+
+// methodTemplates - the templates overriding the generation of the methods, by method name
+var methodTemplates = map[string]*template.Template{}
+
+// methodData - the data of the method templates: the names of the list type and of the type of its members and, for
+// the cross-type methods, the target type, its name in the method names, eg. 'String' for 'MapString', which is empty
+// when the target is the type itself, and its list type, along with the values of the options of the methods and the
+// idioms selected by -go: the name of the empty interface and whether the slices package is used
+type methodData struct {
+	List       string
+	Type       string
+	TargetType string
+	TargetName string
+	TargetList string
+
+	Copy         bool
+	StringSep    string
+	StringMax    int
+	SQLEncoding  string
+	JSONNilEmpty bool
+
+	Any    string
+	Slices bool
+}
+
+// templateFuncs - the functions available in the method templates
+var templateFuncs = template.FuncMap{
+	"title":           getExportedName,
+	"quote":           strconv.Quote,
+	"sliceExpr":       getSliceExpr,
+	"funcName":        getFuncName,
+	"constructorName": getConstructorName,
+	"wrapperName":     getWrapperName,
+	"pairName":        getPairTypeName,
+	"pairList":        getPairListName,
+}
+
+// parseMethodTemplate - parse the built-in template text of the method name
+func parseMethodTemplate(name, text string) *template.Template {
+	return template.Must(template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text))
+}
+
+// executeMethodTemplate - execute the method template tmpl for the list listName of typeName and, for the cross-type
+// methods, the target type targetType named targetTypeName
+func executeMethodTemplate(tmpl *template.Template, listName, typeName, targetType, targetTypeName string) string {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, getMethodData(listName, typeName, targetType, targetTypeName)); err != nil {
+		fatalf("template '%s' failed for type '%s': %s", tmpl.Name(), typeName, err)
+	}
+	return buf.String()
+}
+
+// requirements - the functions checking the requirements which the generators may have on their types, by name
+var requirements = map[string]func(typeName, targetType string) bool{
+	"comparable": isComparableType,
+	"ordered":    isOrderedType,
+	"numeric":    isNumericType,
+	"nilable":    isNilableType,
+	"encodable":  isEncodableType,
+}
+
+// addTemplateGenerator - add a generator of the method name from the template text, which is generated for each
+// target type if crossType is set, only when given in -methods if optIn is set, with the imports and for the types
+// meeting the requirement requires if it is not empty
+func addTemplateGenerator(name, text string, crossType, optIn bool, imports []string, requires string) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		fatalf("generator '%s' template is not valid: %s", name, err)
+	}
+
+	gen := Generator{
+		NeedMapToMap: crossType,
+		OptIn:        optIn,
+		Imports:      imports,
+		Requires:     requires,
+		Method: func(listName, typeName, targetType, targetTypeName string) string {
+			return "\n" + executeMethodTemplate(tmpl, listName, typeName, targetType, targetTypeName) + "\n"
+		},
+	}
+	if requires != "" {
+		supports, ok := requirements[requires]
+		if !ok {
+			fatalf("generator '%s' requirement '%s' is not valid, the valid requirements are comparable, ordered, numeric, nilable and encodable", name, requires)
+		}
+		gen.Supports = supports
+	}
+	if err := RegisterGenerator(name, gen); err != nil {
+		fatalf("%s", err)
+	}
+}
+
+// getMethodData - get the data of the method templates for the list listName of typeName and, for the cross-type
+// methods, the target type targetType named targetTypeName
+func getMethodData(listName, typeName, targetType, targetTypeName string) methodData {
+	data := methodData{
+		List:         listName,
+		Type:         typeName,
+		TargetType:   targetType,
+		TargetList:   listName,
+		Copy:         *copyResults,
+		StringSep:    *stringSep,
+		StringMax:    *stringMax,
+		SQLEncoding:  *sqlEncoding,
+		JSONNilEmpty: *jsonEmpty,
+		Any:          "interface{}",
+		Slices:       isGoAtLeast(slicesMinor),
+	}
+	if isGoAtLeast(anyMinor) {
+		data.Any = "any"
+	}
+	if targetType == "" {
+		data.TargetType = typeName
+	}
+	if targetTypeName != "" {
+		targetTypeName = strings.TrimPrefix(targetTypeName, "*")
+		data.TargetName = getExportedName(targetTypeName)
+		data.TargetList = getTargetListName(targetType, targetTypeName)
+	}
+	return data
+}
+
+// loadTemplates - load the templates of the directory dir, which override the generation of the methods they are
+// named after
+func loadTemplates(dir string) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+templateExt))
+	if err != nil {
+		fatalf("-templates directory '%s' is not valid: %s", dir, err)
+	}
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), templateExt)
+		if len(generators.Filter(func(gen Generator) bool { return gen.Name == name })) == 0 {
+			fatalf("-templates file '%s' is not named after a method", path)
+		}
+		text, err := ioutil.ReadFile(path)
+		if err != nil {
+			fatalf("reading -templates file: %s", err)
+		}
+		tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			fatalf("-templates file '%s' is not a valid template: %s", path, err)
+		}
+		methodTemplates[name] = tmpl
+	}
+}
+
+// getTemplateImports - get the import paths of the packages used by the method templates, which they list, separated by
+// spaces, in a template named 'imports', eg. '{{define "imports"}}log time{{end}}'
+func getTemplateImports() []string {
+	imports := []string{}
+	for name, tmpl := range methodTemplates {
+		if tmpl.Lookup("imports") == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, "imports", nil); err != nil {
+			fatalf("-templates template '%s' has invalid imports: %s", name, err)
+		}
+		imports = append(imports, strings.Fields(buf.String())...)
+	}
+	return imports
+}
+
+// getMethodSource - get the source of the method generated by gen for the list listName of typeName and, for the
+// cross-type methods, the target type targetType named targetTypeName, from its template if it is overridden
+func getMethodSource(gen Generator, listName, typeName, targetType, targetTypeName string) string {
+	tmpl, ok := methodTemplates[gen.Name]
+	if !ok {
+		return gen.Method(listName, typeName, targetType, targetTypeName)
+	}
+	return "\n" + executeMethodTemplate(tmpl, listName, typeName, targetType, targetTypeName) + "\n"
+}
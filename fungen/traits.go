@@ -0,0 +1,290 @@
+package fungen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// checkFileName - the name of the file declaring the types checked in the target package
+const checkFileName = "fungen_check.go"
+
+// traitImplications - the valid traits along with the traits they imply: numeric types are ordered and ordered types
+// are comparable
+var traitImplications = map[string][]string{
+	"comparable": {},
+	"ordered":    {"comparable"},
+	"numeric":    {"ordered", "comparable"},
+}
+
+var (
+	// typeTraits - the traits given for the types in -types and -traits, by type
+	typeTraits = map[string]map[string]bool{}
+	// detectedTraits - the traits detected for the named types, by name
+	detectedTraits = map[string]map[string]bool{}
+	// packageFiles - the parsed files of the target package, parsed once when first needed
+	packageFiles []*ast.File
+	packageFset  *token.FileSet
+	typeImporter gotypes.Importer = packageImporter{}
+)
+
+// addTypeTraits - add the traits to the traits given for typeName, along with the traits they imply
+func addTypeTraits(typeName string, traits []string) {
+	if typeTraits[typeName] == nil {
+		typeTraits[typeName] = map[string]bool{}
+	}
+	for _, trait := range traits {
+		trait = strings.TrimSpace(trait)
+		implied, ok := traitImplications[trait]
+		if !ok {
+			fatalf("unknown trait '%s' for type '%s', the valid traits are %s", trait, typeName, strings.Join(getTraitNames(), ", "))
+		}
+		typeTraits[typeName][trait] = true
+		for _, t := range implied {
+			typeTraits[typeName][t] = true
+		}
+	}
+}
+
+// getTraitNames - get the sorted names of the valid traits
+func getTraitNames() []string {
+	names := []string{}
+	for name := range traitImplications {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// addTraitsOption - add the traits from the -traits option, a comma-separated list of types with their plus (+)
+// separated traits, eg. 'Celsius+numeric,UserID+comparable'. The types can be given by their names in m
+func addTraitsOption(traitsStr string, m map[string]string) {
+	if traitsStr == "" {
+		return
+	}
+
+	for _, t := range splitTopLevel(traitsStr, ',') {
+		tParts := splitTopLevel(t, '+')
+		typeName := unquote(tParts[0])
+		for k, v := range m {
+			if v == typeName {
+				typeName = k
+			}
+		}
+		parseTypeExpr(typeName)
+		addTypeTraits(typeName, tParts[1:])
+	}
+}
+
+// getTraits - get the traits of typeName, which are the traits given for it along with the traits detected from its
+// structure and, for the named types, from their declarations in the target package or its imports
+func getTraits(typeName string) map[string]bool {
+	traits := getExprTraits(parseTypeExpr(typeName))
+	for trait := range typeTraits[typeName] {
+		traits[trait] = true
+	}
+	return traits
+}
+
+// getExprTraits - get the traits of the type expression expr
+func getExprTraits(expr ast.Expr) map[string]bool {
+	comparable := map[string]bool{"comparable": true}
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return getExprTraits(expr.X)
+	case *ast.StarExpr, *ast.ChanType, *ast.InterfaceType:
+		return comparable
+	case *ast.ArrayType:
+		if expr.Len != nil && getExprTraits(expr.Elt)["comparable"] {
+			return comparable
+		}
+	case *ast.StructType:
+		for _, field := range expr.Fields.List {
+			if !getExprTraits(field.Type)["comparable"] {
+				return map[string]bool{}
+			}
+		}
+		return comparable
+	case *ast.Ident, *ast.SelectorExpr:
+		name := gotypes.ExprString(expr)
+		traits := map[string]bool{}
+		for trait := range getNamedTypeTraits(name) {
+			traits[trait] = true
+		}
+		for trait := range typeTraits[name] {
+			traits[trait] = true
+		}
+		return traits
+	}
+	return map[string]bool{}
+}
+
+// getNamedTypeTraits - get the traits detected for the named type name, which is either predeclared, declared in the
+// target package or qualified by the name of an imported package. Types which cannot be found have no traits
+func getNamedTypeTraits(name string) map[string]bool {
+	if traits, ok := detectedTraits[name]; ok {
+		return traits
+	}
+
+	var typ gotypes.Type
+	if obj, ok := gotypes.Universe.Lookup(name).(*gotypes.TypeName); ok {
+		typ = obj.Type()
+	} else {
+		typ = lookupPackageType(name)
+	}
+
+	traits := map[string]bool{}
+	if typ != nil {
+		if gotypes.Comparable(typ) {
+			traits["comparable"] = true
+		}
+		if basic, ok := typ.Underlying().(*gotypes.Basic); ok {
+			if basic.Info()&gotypes.IsOrdered != 0 {
+				traits["ordered"] = true
+			}
+			if basic.Info()&gotypes.IsNumeric != 0 && basic.Info()&gotypes.IsComplex == 0 {
+				traits["numeric"] = true
+			}
+		}
+	}
+	detectedTraits[name] = traits
+	return traits
+}
+
+// lookupPackageType - get the type of the type expression name in the target package, or nil if it cannot be found
+func lookupPackageType(name string) gotypes.Type {
+	typ, _ := checkPackageType(name)
+	return typ
+}
+
+// checkPackageType - type-check the target package, in the output directory, along with a declaration
+// using the type expression name and return its type, or nil and the first error of the declaration if it is not
+// valid. The other errors of the package are ignored since it may use the types being generated
+func checkPackageType(name string) (gotypes.Type, error) {
+	loadPackageFiles()
+
+	pkgName := *packageName
+	if len(packageFiles) > 0 {
+		pkgName = packageFiles[0].Name.Name
+	}
+	src := "package " + pkgName + "\n"
+	for _, pkg := range getPackageNames(name) {
+		src += "import " + getImportSpec(pkg, getImportPathsMap(*importPaths)) + "\n"
+	}
+	src += "var _ " + name + "\n"
+	file, err := parser.ParseFile(packageFset, checkFileName, src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkErr error
+	info := &gotypes.Info{Types: map[ast.Expr]gotypes.TypeAndValue{}}
+	conf := gotypes.Config{Importer: typeImporter, Error: func(err error) {
+		if typeErr, ok := err.(gotypes.Error); ok && checkErr == nil && typeErr.Fset.Position(typeErr.Pos).Filename == checkFileName {
+			checkErr = errors.New(typeErr.Msg)
+		}
+	}}
+	conf.Check(pkgName, packageFset, append(packageFiles, file), info)
+
+	spec := file.Decls[len(file.Decls)-1].(*ast.GenDecl).Specs[0].(*ast.ValueSpec)
+	tv, ok := info.Types[spec.Type]
+	if checkErr != nil || !ok || !tv.IsType() || tv.Type == gotypes.Typ[gotypes.Invalid] {
+		return nil, checkErr
+	}
+	return tv.Type, nil
+}
+
+// loadPackageFiles - parse the files of the target package, in the output directory, once
+func loadPackageFiles() {
+	if packageFset == nil {
+		packageFset = token.NewFileSet()
+		packageFiles = parsePackageFiles(packageFset, getOutputDir(), 0)
+	}
+}
+
+// validateType - check that typeName, from the option named option, can be used as the member type of a list in the
+// target package, and return the error if it cannot. The types are only checked if the target package has files
+func validateType(option, typeName string) error {
+	loadPackageFiles()
+	if len(packageFiles) == 0 {
+		return nil
+	}
+	if _, err := checkPackageType(typeName); err != nil {
+		return fmt.Errorf("%s type '%s' cannot be used in package %s: %s", option, typeName, packageFiles[0].Name.Name, err)
+	}
+	return nil
+}
+
+// parsePackageFiles - parse the non-test Go files in dir which belong to the same package, except the files generated
+// by fungen, with the parser mode
+func parsePackageFiles(fset *token.FileSet, dir string, mode parser.Mode) []*ast.File {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	files := []*ast.File{}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		src, err := ioutil.ReadFile(path)
+		if err != nil || isGeneratedSource(src) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, src, mode)
+		if err != nil || len(files) > 0 && file.Name.Name != files[0].Name.Name {
+			continue
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// isGeneratedSource - whether a line of the source src before the package clause marks it as generated by fungen
+func isGeneratedSource(src []byte) bool {
+	for _, line := range bytes.Split(src, []byte("\n")) {
+		if bytes.HasPrefix(line, []byte("package ")) {
+			break
+		}
+		if bytes.Contains(line, []byte(generatedMarker)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGeneratedFile - whether the file path was generated by fungen
+func isGeneratedFile(path string) bool {
+	src, err := ioutil.ReadFile(path)
+	return err == nil && isGeneratedSource(src)
+}
+
+// getSupportedType - get the type whose traits decide whether a generator supports a list of typeName, which is the
+// target type of the cross-type methods and typeName itself otherwise
+func getSupportedType(typeName, targetType string) string {
+	if targetType != "" {
+		return targetType
+	}
+	return typeName
+}
+
+// isComparableType - whether the members of the type can be compared with ==, and so be used as map keys, which
+// slices, maps, functions and the types containing them cannot be
+func isComparableType(typeName, targetType string) bool {
+	return getTraits(getSupportedType(typeName, targetType))["comparable"]
+}
+
+// isOrderedType - whether the members of the type can be ordered with <
+func isOrderedType(typeName, targetType string) bool {
+	return getTraits(getSupportedType(typeName, targetType))["ordered"]
+}
+
+// isNumericType - whether the members of the type can be added and ordered, which excludes the complex types
+func isNumericType(typeName, targetType string) bool {
+	return getTraits(getSupportedType(typeName, targetType))["numeric"]
+}
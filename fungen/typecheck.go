@@ -0,0 +1,90 @@
+package fungen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	gotypes "go/types"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// snippetContext - the number of lines shown before and after the offending line of a generated file
+const snippetContext = 2
+
+// checkGeneratedFiles - type-check the generated files, by file name, along with the other files of the target
+// package, including the files generated by other runs of fungen, and return the first error of a generated file
+// with the offending lines. The files are only checked if the target package has files, as with validateType. With
+// -subpackage, they are checked along with the files generated in the subpackage, which imports the target package
+func checkGeneratedFiles(files map[string]string) error {
+	loadPackageFiles()
+	if len(packageFiles) == 0 {
+		return nil
+	}
+	pkgName := packageFiles[0].Name.Name
+	dir := getOutputDir()
+	importer := typeImporter
+	checked := append([]*ast.File{}, packageFiles...)
+	if *subpackage != "" {
+		pkgName, dir, importer, checked = *packageName, filepath.Dir(getOutputPath(*outputName)), getSubpackageImporter(), nil
+	}
+
+	generated := map[string]string{}
+	for fileName, src := range files {
+		generated[filepath.Clean(getOutputPath(fileName))] = src
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, path := range paths {
+		if _, ok := generated[filepath.Clean(path)]; ok || strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		src, err := ioutil.ReadFile(path)
+		if err != nil || !isGeneratedSource(src) {
+			continue
+		}
+		if file, err := parser.ParseFile(packageFset, path, src, 0); err == nil && file.Name.Name == pkgName {
+			checked = append(checked, file)
+		}
+	}
+	for _, path := range getSortedKeys(generated) {
+		file, err := parser.ParseFile(packageFset, path, generated[path], 0)
+		if err != nil {
+			return err
+		}
+		checked = append(checked, file)
+	}
+
+	var checkErr error
+	conf := gotypes.Config{Importer: importer, Error: func(err error) {
+		typeErr, ok := err.(gotypes.Error)
+		if !ok || checkErr != nil || strings.HasPrefix(typeErr.Msg, "could not import") {
+			return
+		}
+		pos := typeErr.Fset.Position(typeErr.Pos)
+		if src, ok := generated[pos.Filename]; ok {
+			checkErr = fmt.Errorf("%s: %s\n%s", pos, typeErr.Msg, getSnippet(src, pos.Line))
+		}
+	}}
+	conf.Check(pkgName, packageFset, checked, nil)
+	return checkErr
+}
+
+// getSnippet - get the lines of src around the line numbered line, starting at 1, numbered and with the line itself
+// marked
+func getSnippet(src string, line int) string {
+	lines := strings.Split(src, "\n")
+	snippet := ""
+	for i := line - snippetContext; i <= line+snippetContext; i++ {
+		if i < 1 || i > len(lines) {
+			continue
+		}
+		marker := " "
+		if i == line {
+			marker = ">"
+		}
+		snippet += fmt.Sprintf("%s %4d | %s\n", marker, i, lines[i-1])
+	}
+	return snippet
+}
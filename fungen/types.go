@@ -0,0 +1,178 @@
+package fungen
+
+import (
+	"go/ast"
+	"go/parser"
+	"sort"
+	"strings"
+)
+
+// splitTopLevel - split s around the separators sep which are not nested in brackets or single quotes, so that
+// composite types like 'func(a, b int) bool' or 'struct{ a, b int }' are not split
+func splitTopLevel(s string, sep byte) []string {
+	parts := []string{}
+	depth, quoted, start := 0, false, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'':
+			quoted = !quoted
+		case quoted:
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unquote - remove the surrounding spaces and single quotes from a type or name, eg. "'chan int'" becomes "chan int"
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// parseTypeExpr - parse the type expression typeName
+func parseTypeExpr(typeName string) ast.Expr {
+	expr, err := parser.ParseExpr(typeName)
+	if err != nil {
+		fatalf("'%s' is not a valid type: %s", typeName, err)
+	}
+	return expr
+}
+
+// checkTypeExpr - check that the part of the value of option, at the byte offset start, is a valid type, and stop
+// the generation with the part pointed out in the value if it is not
+func checkTypeExpr(option, value string, start int, part string) {
+	if _, err := parser.ParseExpr(unquote(part)); err != nil {
+		fatalf("%s: '%s' is not a valid type: %s%s", option, unquote(part), err, getTokenPointer(value, start, start+len(part)))
+	}
+}
+
+// getTypeName - get the name used for typeName in generated identifiers, taken from -types when the type is listed
+// there with a name. Otherwise the name is derived from the type: pointer types are named after the type they point
+// to, qualified types after their name without the package and composite types after their parts, eg. 'byteSlice'
+// for '[]byte', 'byteArray16' for '[16]byte', 'stringIntMap' for 'map[string]int', 'intChan' for 'chan int' and 'requestResponseFunc' for
+// 'func(request) response'
+func getTypeName(typeName string, m map[string]string) string {
+	if name, ok := m[typeName]; ok && name != typeName {
+		return strings.TrimPrefix(name, "*")
+	}
+
+	name := deriveTypeName(parseTypeExpr(typeName))
+	if name == "" {
+		fatalf("cannot derive a name for the type '%s', give it one with '%s':Name", typeName, typeName)
+	}
+	return name
+}
+
+// deriveTypeName - derive a name for the type expression expr, or "" if there is no sensible one
+func deriveTypeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.ParenExpr:
+		return deriveTypeName(expr.X)
+	case *ast.StarExpr:
+		return deriveTypeName(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	case *ast.ArrayType:
+		elt := deriveTypeName(expr.Elt)
+		if elt == "" {
+			return ""
+		}
+		switch length := expr.Len.(type) {
+		case nil:
+			return elt + "Slice"
+		case *ast.BasicLit:
+			return elt + "Array" + length.Value
+		case *ast.Ident:
+			return elt + "Array" + getExportedName(length.Name)
+		}
+	case *ast.MapType:
+		if key, value := deriveTypeName(expr.Key), deriveTypeName(expr.Value); key != "" && value != "" {
+			return key + getExportedName(value) + "Map"
+		}
+	case *ast.ChanType:
+		if value := deriveTypeName(expr.Value); value != "" {
+			return value + "Chan"
+		}
+	case *ast.FuncType:
+		name := ""
+		fields := expr.Params.List
+		if expr.Results != nil {
+			fields = append(fields, expr.Results.List...)
+		}
+		for _, field := range fields {
+			fieldName := deriveTypeName(field.Type)
+			if fieldName == "" {
+				return ""
+			}
+			for i := 0; i < len(field.Names) || i == 0; i++ {
+				if name == "" {
+					name = fieldName
+				} else {
+					name += getExportedName(fieldName)
+				}
+			}
+		}
+		if name == "" {
+			return "func"
+		}
+		return name + "Func"
+	case *ast.Ellipsis:
+		if elt := deriveTypeName(expr.Elt); elt != "" {
+			return elt + "Slice"
+		}
+	}
+	return ""
+}
+
+// getPackageNames - get the names of the packages of the qualified types in typeName, eg. 'time' for
+// 'map[string]time.Time'
+func getPackageNames(typeName string) []string {
+	names := map[string]bool{}
+	ast.Inspect(parseTypeExpr(typeName), func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok {
+				names[x.Name] = true
+			}
+		}
+		return true
+	})
+
+	result := []string{}
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// isNilableType - whether the members of typeName can be compared to nil. Named types are assumed not to be
+func isNilableType(typeName, _ string) bool {
+	switch expr := parseTypeExpr(typeName).(type) {
+	case *ast.StarExpr, *ast.FuncType, *ast.MapType, *ast.ChanType, *ast.InterfaceType:
+		return true
+	case *ast.ArrayType:
+		return expr.Len == nil
+	}
+	return false
+}
+
+// isEncodableType - whether the members of typeName can be encoded, as JSON or printed with fmt for example, which
+// function and channel types cannot be
+func isEncodableType(typeName, _ string) bool {
+	switch parseTypeExpr(typeName).(type) {
+	case *ast.FuncType, *ast.ChanType:
+		return false
+	}
+	return true
+}
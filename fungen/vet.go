@@ -0,0 +1,100 @@
+package fungen
+
+import (
+	"encoding/json"
+	"errors"
+	"go/build/constraint"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// vetGeneratedFiles - run go vet on the target package with the generated files, by file name, in place of the files
+// on disk and return the findings reported in the generated files. The generated files are given to go vet with an
+// overlay, so nothing is written to the package
+func vetGeneratedFiles(files map[string]string) ([]string, error) {
+	dir, err := filepath.Abs(getOutputDir())
+	if err != nil {
+		return nil, err
+	}
+	tmpDir, err := ioutil.TempDir("", "fungen")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	replace := map[string]string{}
+	names := map[string]bool{}
+	for fileName, src := range files {
+		path := getOutputPath(fileName)
+		if fileName == "-" {
+			path = checkFileName
+		}
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		tmpPath := filepath.Join(tmpDir, filepath.Base(path))
+		if err := ioutil.WriteFile(tmpPath, []byte(src), 0644); err != nil {
+			return nil, err
+		}
+		replace[path] = tmpPath
+		names[filepath.Base(path)] = true
+	}
+	overlay, err := json.Marshal(map[string]map[string]string{"Replace": replace})
+	if err != nil {
+		return nil, err
+	}
+	overlayPath := filepath.Join(tmpDir, "overlay.json")
+	if err := ioutil.WriteFile(overlayPath, overlay, 0644); err != nil {
+		return nil, err
+	}
+
+	args := []string{"vet", "-overlay", overlayPath}
+	if tags := getVetTags(*buildTags); len(tags) > 0 {
+		args = append(args, "-tags", strings.Join(tags, ","))
+	}
+	cmd := exec.Command("go", append(args, ".")...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if _, ok := err.(*exec.ExitError); err != nil && !ok {
+		return nil, err
+	}
+
+	// go vet exits with status 1 for its findings as well as for the errors of the package, so only the lines about
+	// the generated files are kept. It failing without reporting about any file means that it could not run at all
+	findings := []string{}
+	reported := false
+	for _, line := range strings.Split(string(output), "\n") {
+		if i := strings.Index(line, ".go:"); i >= 0 {
+			reported = true
+			// the generated files are reported at their paths in the overlay, so they are reported by name instead
+			if name := filepath.Base(line[:i+len(".go")]); names[name] {
+				findings = append(findings, name+line[i+len(".go"):])
+			}
+		}
+	}
+	if err != nil && !reported {
+		return nil, errors.New(strings.TrimSpace(string(output)))
+	}
+	return findings, nil
+}
+
+// getVetTags - get the build tags for which the build constraint expression expr, from -tags, is satisfied, so that
+// go vet includes the generated files. The tags are only found if setting all the tags of expr satisfies it
+func getVetTags(expr string) []string {
+	if expr == "" {
+		return nil
+	}
+	parsed, err := constraint.Parse("//go:build " + expr)
+	if err != nil {
+		return nil
+	}
+	tags := []string{}
+	if !parsed.Eval(func(tag string) bool { tags = append(tags, tag); return true }) {
+		return nil
+	}
+	return tags
+}
@@ -0,0 +1,70 @@
+package fungen
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// watchInterval - the interval between the checks for changes of the watched files
+const watchInterval = 500 * time.Millisecond
+
+// watchFiles - run fungen again, with the same flags except -watch, whenever the files of the target package or the
+// configuration file change, until it is interrupted
+func watchFiles() {
+	executable, err := os.Executable()
+	if err != nil {
+		fatalf("finding the fungen executable for -watch: %s", err)
+	}
+	args := []string{}
+	for _, arg := range os.Args[1:] {
+		if name := strings.TrimLeft(arg, "-"); name != "watch" && !strings.HasPrefix(name, "watch=") {
+			args = append(args, arg)
+		}
+	}
+
+	lastState := ""
+	for {
+		if state := getWatchState(); state != lastState {
+			lastState = state
+			cmd := exec.Command(executable, args...)
+			cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+			if err := cmd.Run(); err == nil {
+				noticef("generated at %s", time.Now().Format("15:04:05"))
+			}
+		}
+		time.Sleep(watchInterval)
+	}
+}
+
+// getWatchState - get a description of the modification times and sizes of the watched files, which are the Go files
+// of the target package, except the ones generated by fungen, the configuration file, the header file and the
+// method templates
+func getWatchState() string {
+	paths, _ := filepath.Glob(filepath.Join(getOutputDir(), "*.go"))
+	if *configFile != "" {
+		paths = append(paths, *configFile)
+	} else {
+		paths = append(paths, defaultConfigFile)
+	}
+	if *headerFile != "" {
+		paths = append(paths, *headerFile)
+	}
+	if *templates != "" {
+		templatePaths, _ := filepath.Glob(filepath.Join(*templates, "*"+templateExt))
+		paths = append(paths, templatePaths...)
+	}
+
+	state := ""
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || isGeneratedFile(path) {
+			continue
+		}
+		state += fmt.Sprintf("%s %d %d\n", path, info.ModTime().UnixNano(), info.Size())
+	}
+	return state
+}
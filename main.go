@@ -0,0 +1,11 @@
+package main
+
+import (
+	"os"
+
+	"github.com/kulshekhar/fungen/fungen"
+)
+
+func main() {
+	fungen.Main(os.Args[1:])
+}